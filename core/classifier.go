@@ -0,0 +1,57 @@
+package core
+
+import (
+	"sort"
+
+	"golearn-lite/matrix"
+)
+
+// Classifier is a Model whose Predict returns labels drawn from Classes().
+// Models such as KNN and DecisionTree serve either role depending on their
+// Task, so the role is also reported at run time by IsClassifier.
+type Classifier interface {
+	Model
+	Classes() []float64
+	IsClassifier() bool
+}
+
+// Regressor is a Model whose Predict returns continuous values.
+type Regressor interface {
+	Model
+	IsRegressor() bool
+}
+
+// ProbabilisticClassifier exposes per-class probabilities. PredictProba
+// returns an n×len(Classes()) matrix whose column k holds the probability
+// of Classes()[k]; Classes are sorted in increasing order.
+type ProbabilisticClassifier interface {
+	Classifier
+	PredictProba(X matrix.Matrix) (matrix.Matrix, error)
+}
+
+func IsClassifier(m Model) bool {
+	c, ok := m.(Classifier)
+	return ok && c.IsClassifier()
+}
+
+func IsRegressor(m Model) bool {
+	r, ok := m.(Regressor)
+	return ok && r.IsRegressor()
+}
+
+// UniqueClasses returns the distinct labels of y in increasing order, the
+// column order PredictProba uses.
+func UniqueClasses(y []float64) []float64 {
+	seen := make(map[float64]bool)
+	var classes []float64
+
+	for _, v := range y {
+		if !seen[v] {
+			seen[v] = true
+			classes = append(classes, v)
+		}
+	}
+	sort.Float64s(classes)
+
+	return classes
+}
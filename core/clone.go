@@ -0,0 +1,67 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Cloner is implemented by models that Clone cannot copy through Params
+// alone, such as composites that hold other models.
+type Cloner interface {
+	Clone() (Model, error)
+}
+
+// TransformerCloner is the Cloner counterpart for transformers.
+type TransformerCloner interface {
+	Clone() (Transformer, error)
+}
+
+// Clone returns a new, unfitted model with the same parameters as m. Models
+// implementing Cloner clone themselves; any other model must be a pointer
+// to a struct whose zero value, once given m's Params, behaves like m.
+func Clone(m Model) (Model, error) {
+	if c, ok := m.(Cloner); ok {
+		return c.Clone()
+	}
+
+	out, err := cloneByParams(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.(Model), nil
+}
+
+// CloneTransformer returns a new, unfitted transformer with the same
+// parameters as t, following the same rules as Clone.
+func CloneTransformer(t Transformer) (Transformer, error) {
+	if c, ok := t.(TransformerCloner); ok {
+		return c.Clone()
+	}
+
+	out, err := cloneByParams(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.(Transformer), nil
+}
+
+func cloneByParams(v interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot clone %T: not a non-nil pointer to a struct", v)
+	}
+
+	p, ok := v.(Params)
+	if !ok {
+		return nil, fmt.Errorf("cannot clone %T: it implements neither Params nor Cloner", v)
+	}
+
+	out := reflect.New(rv.Elem().Type()).Interface()
+	if err := out.(Params).SetParams(p.GetParams()); err != nil {
+		return nil, fmt.Errorf("cannot clone %T: %w", v, err)
+	}
+
+	return out, nil
+}
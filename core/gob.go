@@ -0,0 +1,26 @@
+package core
+
+import (
+	"encoding/gob"
+	"os"
+)
+
+// LoadGob decodes the gob file at path into a zero T and only then replaces
+// *dst. Decoding straight into a receiver would keep its values for every
+// field the file leaves out, and gob leaves out zero values: a saved false
+// or nil would come back as whatever the receiver held.
+func LoadGob[T any](path string, dst *T) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var v T
+	if err := gob.NewDecoder(f).Decode(&v); err != nil {
+		return err
+	}
+	*dst = v
+
+	return nil
+}
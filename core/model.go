@@ -1,12 +1,27 @@
 package core
 
-import "golearn-lite/matrix"
+import (
+	"errors"
+	"math"
+
+	"golearn-lite/matrix"
+)
 
 type Model interface {
 	Fit(X matrix.Matrix, y []float64) error
 	Predict(X matrix.Matrix) []float64
 }
 
+// Estimator is a Model whose prediction errors are observable. Every model
+// in this module implements it; Predict remains as a wrapper around
+// PredictE that returns nil on error, so existing callers keep compiling.
+type Estimator interface {
+	Model
+	PredictE(X matrix.Matrix) ([]float64, error)
+	IsFitted() bool
+}
+
+// Scorable models return NaN from Score when prediction fails.
 type Scorable interface {
 	Score(X matrix.Matrix, y []float64, metric func(yTrue, yPred []float64) float64) float64
 }
@@ -22,3 +37,31 @@ type Params interface {
 	GetParams() map[string]interface{}
 	SetParams(params map[string]interface{}) error
 }
+
+// Predict is the migration helper for code that holds a plain Model: it
+// uses PredictE when available and otherwise treats a nil result from
+// Predict as a failure.
+func Predict(m Model, X matrix.Matrix) ([]float64, error) {
+	if e, ok := m.(Estimator); ok {
+		return e.PredictE(X)
+	}
+
+	preds := m.Predict(X)
+	if preds == nil && X.Rows > 0 {
+		return nil, errors.New("model returned no predictions")
+	}
+
+	return preds, nil
+}
+
+// Score applies metric to the predictions of predictE. It returns NaN when
+// prediction fails or the lengths differ, so an unfitted model or input of
+// the wrong width cannot pass for a real score.
+func Score(predictE func(X matrix.Matrix) ([]float64, error), X matrix.Matrix, y []float64, metric func(yTrue, yPred []float64) float64) float64 {
+	yPred, err := predictE(X)
+	if err != nil || len(yPred) != len(y) {
+		return math.NaN()
+	}
+
+	return metric(y, yPred)
+}
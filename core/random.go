@@ -0,0 +1,40 @@
+package core
+
+import "math/rand"
+
+// Randomized functions take a *rand.Rand and randomized estimators a
+// RandomState *int64, so the same seed always reproduces the same result.
+// A nil generator or state means "unseeded": a fresh generator with a
+// random seed is created for that call. A *rand.Rand is not safe for
+// concurrent use; give each goroutine its own.
+
+// NewRand returns a generator seeded with seed.
+func NewRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// DefaultRand returns a new generator with a random seed. It never touches
+// the shared global source's state beyond drawing that seed, so concurrent
+// callers do not interfere.
+func DefaultRand() *rand.Rand {
+	return NewRand(rand.Int63())
+}
+
+// RandOrDefault returns rng, or DefaultRand() if rng is nil.
+func RandOrDefault(rng *rand.Rand) *rand.Rand {
+	if rng == nil {
+		return DefaultRand()
+	}
+
+	return rng
+}
+
+// RandFromState returns a generator for an estimator's RandomState field:
+// seeded with *state, or DefaultRand() if state is nil.
+func RandFromState(state *int64) *rand.Rand {
+	if state == nil {
+		return DefaultRand()
+	}
+
+	return NewRand(*state)
+}
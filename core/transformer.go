@@ -0,0 +1,13 @@
+package core
+
+import "golearn-lite/matrix"
+
+// Transformer learns a mapping from training data in Fit and applies it to
+// any later data with Transform, so statistics computed on the training set
+// are reused unchanged at test and serving time.
+type Transformer interface {
+	Fit(X matrix.Matrix) error
+	Transform(X matrix.Matrix) (matrix.Matrix, error)
+	FitTransform(X matrix.Matrix) (matrix.Matrix, error)
+	InverseTransform(X matrix.Matrix) (matrix.Matrix, error)
+}
@@ -0,0 +1,310 @@
+package data
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// arffAttribute is one @attribute declaration.
+type arffAttribute struct {
+	name       string
+	kind       string   // "numeric", "integer", "nominal", "string" or "date"
+	categories []string // nominal only, in declaration order
+	layout     string   // date only, as a time.Parse layout
+}
+
+// LoadARFF reads a Weka ARFF file into a Dataset. Numeric and real
+// attributes load as float columns, integer as int, string as categorical
+// and date as time. Nominal attributes load as categorical columns whose
+// Categories keep the declared order, so ToMatrix codes each value by its
+// position in the declaration. '?' marks a missing value. Sparse data
+// rows are not supported.
+func LoadARFF(path string) (*Dataset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	var attrs []arffAttribute
+	var cells [][]string
+	inData := false
+
+	for lineNo := 1; ; lineNo++ {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if line == "" && err == io.EOF {
+			break
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%") {
+			if err == io.EOF {
+				break
+			}
+			continue
+		}
+
+		switch {
+		case inData:
+			if strings.HasPrefix(line, "{") {
+				return nil, fmt.Errorf("line %d: sparse ARFF data is not supported", lineNo)
+			}
+			row, perr := splitARFF(line)
+			if perr != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, perr)
+			}
+			if len(row) != len(attrs) {
+				return nil, fmt.Errorf("line %d: expected %d values, got %d", lineNo, len(attrs), len(row))
+			}
+			cells = append(cells, row)
+
+		case hasKeyword(line, "@relation"):
+
+		case hasKeyword(line, "@attribute"):
+			attr, perr := parseARFFAttribute(strings.TrimSpace(line[len("@attribute"):]))
+			if perr != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, perr)
+			}
+			attrs = append(attrs, attr)
+
+		case hasKeyword(line, "@data"):
+			inData = true
+
+		default:
+			return nil, fmt.Errorf("line %d: unexpected %q", lineNo, line)
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	if len(attrs) == 0 {
+		return nil, errors.New("ARFF file declares no attributes")
+	}
+
+	columns := make([]*Column, len(attrs))
+	for j, attr := range attrs {
+		col, err := arffColumn(attr, cells, j)
+		if err != nil {
+			return nil, err
+		}
+		columns[j] = col
+	}
+
+	return NewDataset(columns...)
+}
+
+func hasKeyword(line, keyword string) bool {
+	if len(line) < len(keyword) || !strings.EqualFold(line[:len(keyword)], keyword) {
+		return false
+	}
+
+	return len(line) == len(keyword) || line[len(keyword)] == ' ' || line[len(keyword)] == '\t'
+}
+
+func parseARFFAttribute(decl string) (arffAttribute, error) {
+	var attr arffAttribute
+	var rest string
+
+	if decl != "" && (decl[0] == '\'' || decl[0] == '"') {
+		end := strings.IndexByte(decl[1:], decl[0])
+		if end < 0 {
+			return attr, errors.New("unterminated attribute name")
+		}
+		attr.name, rest = decl[1:end+1], decl[end+2:]
+	} else {
+		k := strings.IndexAny(decl, " \t")
+		if k < 0 {
+			return attr, fmt.Errorf("attribute %q has no type", decl)
+		}
+		attr.name, rest = decl[:k], decl[k:]
+	}
+	rest = strings.TrimSpace(rest)
+
+	switch {
+	case strings.HasPrefix(rest, "{"):
+		if !strings.HasSuffix(rest, "}") {
+			return attr, fmt.Errorf("attribute %q: unterminated nominal list", attr.name)
+		}
+		categories, err := splitARFF(rest[1 : len(rest)-1])
+		if err != nil {
+			return attr, fmt.Errorf("attribute %q: %w", attr.name, err)
+		}
+		attr.kind, attr.categories = "nominal", categories
+
+	case strings.EqualFold(rest, "numeric") || strings.EqualFold(rest, "real"):
+		attr.kind = "numeric"
+
+	case strings.EqualFold(rest, "integer"):
+		attr.kind = "integer"
+
+	case strings.EqualFold(rest, "string"):
+		attr.kind = "string"
+
+	case hasKeyword(rest, "date"):
+		attr.kind = "date"
+		attr.layout = "2006-01-02T15:04:05"
+		if format := strings.TrimSpace(rest[len("date"):]); format != "" {
+			attr.layout = javaDateLayout(strings.Trim(format, `'"`))
+		}
+
+	default:
+		return attr, fmt.Errorf("attribute %q: unsupported type %q", attr.name, rest)
+	}
+
+	return attr, nil
+}
+
+// arffColumn builds column j from the raw data cells.
+func arffColumn(attr arffAttribute, cells [][]string, j int) (*Column, error) {
+	n := len(cells)
+	missing := make([]bool, n)
+	for i, row := range cells {
+		missing[i] = row[j] == "?"
+	}
+
+	fail := func(i int) error {
+		return fmt.Errorf("data row %d: %q is not a valid %s value for %q", i+1, cells[i][j], attr.kind, attr.name)
+	}
+
+	switch attr.kind {
+	case "numeric":
+		values := make([]float64, n)
+		for i, row := range cells {
+			if missing[i] {
+				values[i] = math.NaN()
+				continue
+			}
+			v, err := strconv.ParseFloat(row[j], 64)
+			if err != nil {
+				return nil, fail(i)
+			}
+			values[i] = v
+		}
+		return &Column{Name: attr.name, Type: Float, Floats: values, Missing: missing}, nil
+
+	case "integer":
+		values := make([]int64, n)
+		for i, row := range cells {
+			if missing[i] {
+				continue
+			}
+			v, err := strconv.ParseInt(row[j], 10, 64)
+			if err != nil {
+				return nil, fail(i)
+			}
+			values[i] = v
+		}
+		return NewIntColumn(attr.name, values, missing), nil
+
+	case "date":
+		values := make([]time.Time, n)
+		for i, row := range cells {
+			if missing[i] {
+				continue
+			}
+			t, err := time.Parse(attr.layout, row[j])
+			if err != nil {
+				return nil, fail(i)
+			}
+			values[i] = t
+		}
+		return NewTimeColumn(attr.name, values, missing), nil
+	}
+
+	known := make(map[string]bool, len(attr.categories))
+	for _, c := range attr.categories {
+		known[c] = true
+	}
+
+	values := make([]string, n)
+	for i, row := range cells {
+		if missing[i] {
+			continue
+		}
+		if attr.kind == "nominal" && !known[row[j]] {
+			return nil, fail(i)
+		}
+		values[i] = row[j]
+	}
+
+	col := NewCategoricalColumn(attr.name, values, missing)
+	col.Categories = attr.categories
+
+	return col, nil
+}
+
+// splitARFF splits comma-separated values, honouring single and double
+// quotes and backslash escapes inside them, and trims unquoted values.
+func splitARFF(s string) ([]string, error) {
+	var out []string
+
+	for i := 0; ; {
+		for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+			i++
+		}
+
+		var value string
+		if i < len(s) && (s[i] == '\'' || s[i] == '"') {
+			q := s[i]
+			var b strings.Builder
+			i++
+			for ; i < len(s) && s[i] != q; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				b.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, errors.New("unterminated quoted value")
+			}
+			i++
+			value = b.String()
+
+			for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+				i++
+			}
+			if i < len(s) && s[i] != ',' {
+				return nil, errors.New("unexpected text after quoted value")
+			}
+		} else {
+			k := strings.IndexByte(s[i:], ',')
+			if k < 0 {
+				k = len(s) - i
+			}
+			value = strings.TrimSpace(s[i : i+k])
+			i += k
+		}
+		out = append(out, value)
+
+		if i >= len(s) {
+			return out, nil
+		}
+		i++ // skip the comma
+	}
+}
+
+// javaDateLayout converts the common SimpleDateFormat patterns ARFF uses
+// into a time.Parse layout.
+func javaDateLayout(format string) string {
+	r := strings.NewReplacer(
+		"yyyy", "2006", "yy", "06",
+		"MM", "01", "dd", "02",
+		"HH", "15", "mm", "04", "ss", "05",
+		"SSS", "000", "Z", "-0700", "'", "",
+	)
+
+	return r.Replace(format)
+}
@@ -0,0 +1,82 @@
+package data
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const arffInput = `% a comment
+@RELATION weather
+
+@attribute outlook {sunny, overcast, 'rainy day'}
+@attribute temperature numeric
+@attribute humidity integer
+@attribute 'observed at' date "yyyy-MM-dd HH:mm"
+@attribute note string
+
+@data
+sunny, 85, 85, "2024-03-01 12:30", 'it\'s hot'
+'rainy day', ?, 70, ?, plain
+overcast, 64.5, ?, "2024-03-02 08:00", "with, comma"
+`
+
+func TestLoadARFF(t *testing.T) {
+	ds, err := LoadARFF(writeTemp(t, "in.arff", arffInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"outlook", "temperature", "humidity", "observed at", "note"}
+	if got := ds.Names(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("columns %v, want %v", got, want)
+	}
+
+	X, _, err := ds.ToMatrix("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nominal codes follow the declaration, not sorted order
+	for i, code := range []float64{0, 2, 1} {
+		if X.At(i, 0) != code {
+			t.Errorf("outlook row %d coded %v, want %v", i, X.At(i, 0), code)
+		}
+	}
+
+	temp, _ := ds.Column("temperature")
+	if temp.Type != Float || temp.Floats[2] != 64.5 || !temp.IsMissing(1) || !math.IsNaN(temp.Floats[1]) {
+		t.Errorf("temperature = %v, missing %v", temp.Floats, temp.Missing)
+	}
+	humidity, _ := ds.Column("humidity")
+	if humidity.Type != Int || humidity.Ints[1] != 70 || !humidity.IsMissing(2) {
+		t.Errorf("humidity = %v, missing %v", humidity.Ints, humidity.Missing)
+	}
+	observed, _ := ds.Column("observed at")
+	if at := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC); observed.Type != Time || !observed.Times[0].Equal(at) || !observed.IsMissing(1) {
+		t.Errorf("observed at = %v, missing %v", observed.Times, observed.Missing)
+	}
+	note, _ := ds.Column("note")
+	if note.Type != Categorical || note.Strings[0] != "it's hot" || note.Strings[1] != "plain" || note.Strings[2] != "with, comma" {
+		t.Errorf("note = %q", note.Strings)
+	}
+}
+
+func TestLoadARFFErrors(t *testing.T) {
+	header := "@relation r\n@attribute a {x, y}\n@attribute b numeric\n@data\n"
+
+	for name, input := range map[string]string{
+		"sparse row":        header + "{0 x, 1 2}\n",
+		"undeclared value":  header + "z, 1\n",
+		"bad number":        header + "x, abc\n",
+		"wrong width":       header + "x\n",
+		"unterminated":      header + "'x, 1\n",
+		"unsupported type":  "@attribute a relational\n@data\n",
+		"no attributes":     "@relation r\n@data\n",
+		"unexpected header": "@relation r\nhello\n",
+	} {
+		if _, err := LoadARFF(writeTemp(t, "in.arff", input)); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
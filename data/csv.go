@@ -0,0 +1,593 @@
+package data
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golearn-lite/matrix"
+)
+
+// CSVOptions configures the CSV loaders. Start from DefaultCSVOptions; in
+// a zero CSVOptions the target is column 0 and quoting is disabled.
+type CSVOptions struct {
+	HasHeader bool
+	Delimiter rune     // field separator; 0 means ','
+	Quote     rune     // quote character; 0 disables quoting
+	Comment   rune     // lines starting with it are skipped; 0 disables comments
+	NATokens  []string // cells equal to one of these (after trimming spaces) are missing
+
+	// The target is the column named TargetName if set, otherwise the one
+	// at TargetIndex, where negative indices count from the end. NoTarget
+	// loads every selected column as a feature.
+	TargetName  string
+	TargetIndex int
+	NoTarget    bool
+
+	// Columns and ColumnIndices restrict the features to the listed
+	// columns, in that order; by default every non-target column is used.
+	Columns       []string
+	ColumnIndices []int
+
+	ChunkSize int // rows per CSVChunkReader.Next; 0 means 1024
+}
+
+// DefaultCSVOptions reads comma-separated, double-quoted files whose last
+// column is the target.
+func DefaultCSVOptions() CSVOptions {
+	return CSVOptions{
+		Delimiter:   ',',
+		Quote:       '"',
+		NATokens:    []string{"", "NA", "N/A", "NaN", "nan", "null", "NULL"},
+		TargetIndex: -1,
+		ChunkSize:   1024,
+	}
+}
+
+// LoadCSV reads a numeric CSV file whose last column is the target.
+func LoadCSV(path string, hasHeader bool) ([][]float64, []float64, error) {
+	opts := DefaultCSVOptions()
+	opts.HasHeader = hasHeader
+
+	X, y, err := LoadCSVOptions(path, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return X.ToSlices(), y, nil
+}
+
+// LoadCSVOptions reads a numeric CSV file into a feature matrix and target.
+// Missing cells become NaN; any other non-numeric cell is an error. y is
+// nil when opts.NoTarget is set.
+func LoadCSVOptions(path string, opts CSVOptions) (matrix.Matrix, []float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return matrix.Matrix{}, nil, err
+	}
+	defer f.Close()
+
+	cr, err := NewCSVChunkReader(f, opts)
+	if err != nil {
+		return matrix.Matrix{}, nil, err
+	}
+
+	var chunks []matrix.Matrix
+	var y []float64
+	if !opts.NoTarget {
+		y = []float64{}
+	}
+
+	for {
+		X, yChunk, err := cr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return matrix.Matrix{}, nil, err
+		}
+		chunks = append(chunks, X)
+		y = append(y, yChunk...)
+	}
+
+	if len(chunks) == 0 {
+		return matrix.Zeros(0, len(cr.features)), y, nil
+	}
+
+	X, err := matrix.VStack(chunks...)
+	return X, y, err
+}
+
+// CSVChunkReader streams a numeric CSV file a chunk of rows at a time, so
+// files larger than memory can be processed incrementally.
+type CSVChunkReader struct {
+	opts     CSVOptions
+	rr       *recordReader
+	header   []string
+	nFields  int
+	features []int
+	target   int // -1 without a target
+	pending  []string // first data row, read early to count fields
+}
+
+func NewCSVChunkReader(r io.Reader, opts CSVOptions) (*CSVChunkReader, error) {
+	cr := &CSVChunkReader{opts: opts, rr: newRecordReader(r, opts)}
+
+	first, err := cr.rr.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV input is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cr.nFields = len(first)
+	if opts.HasHeader {
+		cr.header = first
+	} else {
+		cr.pending = first
+	}
+
+	cr.target, cr.features, err = resolveCSVColumns(opts, cr.header, cr.nFields)
+	if err != nil {
+		return nil, err
+	}
+	if len(cr.features) == 0 {
+		return nil, errors.New("CSV needs at least one feature column")
+	}
+
+	return cr, nil
+}
+
+// Header returns the header row, or nil if the file has none.
+func (cr *CSVChunkReader) Header() []string {
+	return cr.header
+}
+
+// FeatureNames returns the header names of the feature columns in matrix
+// column order, or nil if the file has no header.
+func (cr *CSVChunkReader) FeatureNames() []string {
+	if cr.header == nil {
+		return nil
+	}
+
+	names := make([]string, len(cr.features))
+	for k, j := range cr.features {
+		names[k] = cr.header[j]
+	}
+
+	return names
+}
+
+// Next returns up to ChunkSize rows. After the last row it returns io.EOF.
+func (cr *CSVChunkReader) Next() (matrix.Matrix, []float64, error) {
+	size := cr.opts.ChunkSize
+	if size <= 0 {
+		size = 1024
+	}
+
+	data := make([]float64, 0, size*len(cr.features))
+	var y []float64
+	if cr.target >= 0 {
+		y = make([]float64, 0, size)
+	}
+
+	rows := 0
+	for rows < size {
+		record, err := cr.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return matrix.Matrix{}, nil, err
+		}
+
+		for _, j := range cr.features {
+			v, err := cr.parse(record[j], j)
+			if err != nil {
+				return matrix.Matrix{}, nil, err
+			}
+			data = append(data, v)
+		}
+		if cr.target >= 0 {
+			v, err := cr.parse(record[cr.target], cr.target)
+			if err != nil {
+				return matrix.Matrix{}, nil, err
+			}
+			y = append(y, v)
+		}
+		rows++
+	}
+
+	if rows == 0 {
+		return matrix.Matrix{}, nil, io.EOF
+	}
+
+	X, err := matrix.NewFromSlice(rows, len(cr.features), data)
+	return X, y, err
+}
+
+func (cr *CSVChunkReader) next() ([]string, error) {
+	if cr.pending != nil {
+		record := cr.pending
+		cr.pending = nil
+		return record, nil
+	}
+
+	record, err := cr.rr.Read()
+	if err != nil {
+		return nil, err
+	}
+	if len(record) != cr.nFields {
+		return nil, fmt.Errorf("line %d: expected %d fields, got %d", cr.rr.line, cr.nFields, len(record))
+	}
+
+	return record, nil
+}
+
+func (cr *CSVChunkReader) parse(cell string, col int) (float64, error) {
+	cell = strings.TrimSpace(cell)
+	if isNA(cell, cr.opts.NATokens) {
+		return math.NaN(), nil
+	}
+
+	v, err := strconv.ParseFloat(cell, 64)
+	if err != nil {
+		return 0, fmt.Errorf("line %d, column %d: %q is not a number", cr.rr.line, col, cell)
+	}
+
+	return v, nil
+}
+
+// resolveCSVColumns picks the target and feature column indices.
+func resolveCSVColumns(opts CSVOptions, header []string, nFields int) (target int, features []int, err error) {
+	byName := make(map[string]int, len(header))
+	for j, name := range header {
+		byName[strings.TrimSpace(name)] = j
+	}
+	lookup := func(name string) (int, error) {
+		if header == nil {
+			return 0, fmt.Errorf("column %q selected by name but the CSV has no header", name)
+		}
+		j, ok := byName[name]
+		if !ok {
+			return 0, fmt.Errorf("no column named %q", name)
+		}
+		return j, nil
+	}
+
+	target = -1
+	if !opts.NoTarget {
+		if opts.TargetName != "" {
+			if target, err = lookup(opts.TargetName); err != nil {
+				return 0, nil, err
+			}
+		} else {
+			target = opts.TargetIndex
+			if target < 0 {
+				target += nFields
+			}
+			if target < 0 || target >= nFields {
+				return 0, nil, fmt.Errorf("target index %d out of range for %d columns", opts.TargetIndex, nFields)
+			}
+		}
+	}
+
+	for _, name := range opts.Columns {
+		j, err := lookup(name)
+		if err != nil {
+			return 0, nil, err
+		}
+		features = append(features, j)
+	}
+	for _, j := range opts.ColumnIndices {
+		if j < 0 || j >= nFields {
+			return 0, nil, fmt.Errorf("column index %d out of range for %d columns", j, nFields)
+		}
+		features = append(features, j)
+	}
+
+	if len(opts.Columns) == 0 && len(opts.ColumnIndices) == 0 {
+		for j := 0; j < nFields; j++ {
+			if j != target {
+				features = append(features, j)
+			}
+		}
+	}
+
+	for _, j := range features {
+		if j == target {
+			return 0, nil, fmt.Errorf("column %d is both a feature and the target", j)
+		}
+	}
+
+	return target, features, nil
+}
+
+func isNA(cell string, tokens []string) bool {
+	for _, t := range tokens {
+		if cell == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LoadCSVDataset reads a CSV file into a Dataset, inferring each column's
+// type from its non-missing cells: int, then float, then bool, then time
+// (RFC 3339, "2006-01-02 15:04:05" or "2006-01-02"), falling back to
+// categorical. Columns that are entirely missing load as float. Without a
+// header, columns are named col0, col1, and so on.
+//
+// The target is kept as an ordinary column; Columns and ColumnIndices
+// restrict the columns loaded and may include the target; if they do not,
+// the target is appended after them.
+func LoadCSVDataset(path string, opts CSVOptions) (*Dataset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rr := newRecordReader(f, opts)
+	first, err := rr.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV input is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nFields := len(first)
+	var header []string
+	var records [][]string
+	if opts.HasHeader {
+		header = first
+	} else {
+		records = append(records, first)
+	}
+
+	// The target is an ordinary column here, so it is resolved apart from
+	// the features and may also be listed among them
+	featureOpts, targetOpts := opts, opts
+	featureOpts.NoTarget = true
+	targetOpts.Columns, targetOpts.ColumnIndices = nil, nil
+
+	_, selected, err := resolveCSVColumns(featureOpts, header, nFields)
+	if err != nil {
+		return nil, err
+	}
+	target, _, err := resolveCSVColumns(targetOpts, header, nFields)
+	if err != nil {
+		return nil, err
+	}
+	if target >= 0 && !slices.Contains(selected, target) {
+		selected = append(selected, target)
+	}
+	if len(opts.Columns) == 0 && len(opts.ColumnIndices) == 0 {
+		selected = selected[:0]
+		for j := 0; j < nFields; j++ {
+			selected = append(selected, j)
+		}
+	}
+
+	for {
+		record, err := rr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) != nFields {
+			return nil, fmt.Errorf("line %d: expected %d fields, got %d", rr.line, nFields, len(record))
+		}
+		records = append(records, record)
+	}
+
+	columns := make([]*Column, len(selected))
+	for k, j := range selected {
+		name := fmt.Sprintf("col%d", j)
+		if header != nil {
+			name = strings.TrimSpace(header[j])
+		}
+
+		cells := make([]string, len(records))
+		missing := make([]bool, len(records))
+		for i, record := range records {
+			cells[i] = strings.TrimSpace(record[j])
+			missing[i] = isNA(cells[i], opts.NATokens)
+		}
+		columns[k] = inferColumn(name, cells, missing)
+	}
+
+	return NewDataset(columns...)
+}
+
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+// inferColumn builds the narrowest column type that parses every
+// non-missing cell.
+func inferColumn(name string, cells []string, missing []bool) *Column {
+	n := len(cells)
+
+	floats := make([]float64, n)
+	for i := range floats {
+		floats[i] = math.NaN()
+	}
+
+	ints := make([]int64, n)
+	if parseAll(cells, missing, func(i int, s string) (err error) {
+		ints[i], err = strconv.ParseInt(s, 10, 64)
+		return err
+	}) {
+		return NewIntColumn(name, ints, missing)
+	}
+
+	if parseAll(cells, missing, func(i int, s string) (err error) {
+		floats[i], err = strconv.ParseFloat(s, 64)
+		return err
+	}) || !slices.Contains(missing, false) {
+		return &Column{Name: name, Type: Float, Floats: floats, Missing: missing}
+	}
+
+	bools := make([]bool, n)
+	if parseAll(cells, missing, func(i int, s string) (err error) {
+		bools[i], err = strconv.ParseBool(s)
+		return err
+	}) {
+		return NewBoolColumn(name, bools, missing)
+	}
+
+	times := make([]time.Time, n)
+	if parseAll(cells, missing, func(i int, s string) error {
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				times[i] = t
+				return nil
+			}
+		}
+		return errors.New("not a time")
+	}) {
+		return NewTimeColumn(name, times, missing)
+	}
+
+	strs := make([]string, n)
+	for i, cell := range cells {
+		if !missing[i] {
+			strs[i] = cell
+		}
+	}
+
+	return NewCategoricalColumn(name, strs, missing)
+}
+
+// parseAll applies parse to every non-missing cell and reports whether all
+// succeeded. A column with no present cells matches no type.
+func parseAll(cells []string, missing []bool, parse func(i int, s string) error) bool {
+	present := 0
+
+	for i, cell := range cells {
+		if missing[i] {
+			continue
+		}
+		if err := parse(i, cell); err != nil {
+			return false
+		}
+		present++
+	}
+
+	return present > 0
+}
+
+// recordReader splits delimited text into records. Unlike encoding/csv it
+// accepts any quote character; a doubled quote inside a quoted field is a
+// literal quote, and quoted fields may span lines. Blank lines are skipped.
+type recordReader struct {
+	r       *bufio.Reader
+	delim   rune
+	quote   rune
+	comment rune
+	line    int
+}
+
+func newRecordReader(r io.Reader, opts CSVOptions) *recordReader {
+	delim := opts.Delimiter
+	if delim == 0 {
+		delim = ','
+	}
+
+	return &recordReader{r: bufio.NewReader(r), delim: delim, quote: opts.Quote, comment: opts.Comment}
+}
+
+func (rr *recordReader) Read() ([]string, error) {
+	for {
+		line, err := rr.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if line == "" {
+			continue
+		}
+		if rr.comment != 0 && strings.HasPrefix(line, string(rr.comment)) {
+			continue
+		}
+
+		return rr.split(line)
+	}
+}
+
+func (rr *recordReader) readLine() (string, error) {
+	line, err := rr.r.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return "", io.EOF
+	}
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	rr.line++
+
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r"), nil
+}
+
+func (rr *recordReader) split(line string) ([]string, error) {
+	var fields []string
+	var field strings.Builder
+	inQuotes, fieldStart := false, true
+	startLine := rr.line
+
+	for {
+		for i := 0; i < len(line); {
+			r, size := utf8.DecodeRuneInString(line[i:])
+			i += size
+
+			switch {
+			case inQuotes && r == rr.quote:
+				if next, n := utf8.DecodeRuneInString(line[i:]); i < len(line) && next == rr.quote {
+					field.WriteRune(r)
+					i += n
+				} else {
+					inQuotes = false
+				}
+			case inQuotes:
+				field.WriteRune(r)
+			case r == rr.delim:
+				fields = append(fields, field.String())
+				field.Reset()
+				fieldStart = true
+				continue
+			case r == rr.quote && rr.quote != 0 && fieldStart:
+				inQuotes = true
+			default:
+				field.WriteRune(r)
+			}
+			fieldStart = false
+		}
+
+		if !inQuotes {
+			break
+		}
+
+		next, err := rr.readLine()
+		if err == io.EOF {
+			return nil, fmt.Errorf("line %d: unterminated quoted field", startLine)
+		}
+		if err != nil {
+			return nil, err
+		}
+		field.WriteByte('\n')
+		line = next
+	}
+
+	return append(fields, field.String()), nil
+}
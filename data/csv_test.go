@@ -0,0 +1,177 @@
+package data
+
+import (
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTemp writes content to a file in a per-test directory.
+func writeTemp(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadCSVOptions(t *testing.T) {
+	path := writeTemp(t, "semi.csv", strings.Join([]string{
+		"# exported data",
+		"id;label;'a;b';c",
+		"1;0;1.5;NA",
+		"# a comment between rows",
+		"2;1;'2.5';7",
+	}, "\n"))
+
+	opts := DefaultCSVOptions()
+	opts.HasHeader = true
+	opts.Delimiter = ';'
+	opts.Quote = '\''
+	opts.Comment = '#'
+	opts.TargetName = "label"
+	opts.Columns = []string{"c", "a;b"}
+
+	X, y, err := LoadCSVOptions(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if X.Rows != 2 || X.Cols != 2 {
+		t.Fatalf("X is %dx%d, want 2x2", X.Rows, X.Cols)
+	}
+	if !math.IsNaN(X.At(0, 0)) || X.At(0, 1) != 1.5 || X.At(1, 0) != 7 || X.At(1, 1) != 2.5 {
+		t.Errorf("X = %v", X.Data)
+	}
+	if y[0] != 0 || y[1] != 1 {
+		t.Errorf("y = %v, want [0 1]", y)
+	}
+}
+
+func TestLoadCSVQuotedMultiline(t *testing.T) {
+	path := writeTemp(t, "quoted.csv", "note,x,y\n\"two\nlines, one comma\",1,0\n\"say \"\"hi\"\"\",2,1\n")
+
+	opts := DefaultCSVOptions()
+	opts.HasHeader = true
+	ds, err := LoadCSVDataset(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	note, _ := ds.Column("note")
+	if note.Strings[0] != "two\nlines, one comma" || note.Strings[1] != `say "hi"` {
+		t.Errorf("notes = %q", note.Strings)
+	}
+}
+
+func TestLoadCSVErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		edit    func(*CSVOptions)
+	}{
+		{"text cell", "1,x,0\n", nil},
+		{"ragged row", "1,2,0\n1,0\n", nil},
+		{"unknown column", "a,b\n1,0\n", func(o *CSVOptions) { o.HasHeader = true; o.Columns = []string{"zz"} }},
+		{"name without header", "1,0\n", func(o *CSVOptions) { o.Columns = []string{"a"} }},
+		{"target index", "1,0\n", func(o *CSVOptions) { o.TargetIndex = 5 }},
+		{"target as feature", "a,b\n1,0\n", func(o *CSVOptions) { o.HasHeader = true; o.TargetName = "b"; o.Columns = []string{"b"} }},
+		{"unterminated quote", "\"1,0\n", nil},
+	}
+
+	for _, tt := range tests {
+		opts := DefaultCSVOptions()
+		if tt.edit != nil {
+			tt.edit(&opts)
+		}
+		if _, _, err := LoadCSVOptions(writeTemp(t, "bad.csv", tt.content), opts); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
+
+func TestCSVChunkReader(t *testing.T) {
+	opts := DefaultCSVOptions()
+	opts.HasHeader = true
+	opts.ChunkSize = 2
+
+	cr, err := NewCSVChunkReader(strings.NewReader("x,y\n1,0\n2,1\n3,0\n4,1\n5,0\n"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := cr.FeatureNames(); len(names) != 1 || names[0] != "x" {
+		t.Errorf("feature names %v, want [x]", names)
+	}
+
+	var sizes []int
+	total := 0.0
+	for {
+		X, y, err := cr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(y) != X.Rows {
+			t.Fatalf("%d labels for %d rows", len(y), X.Rows)
+		}
+		sizes = append(sizes, X.Rows)
+		total += X.Sum()
+	}
+
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 {
+		t.Errorf("chunk sizes %v, want [2 2 1]", sizes)
+	}
+	if total != 15 {
+		t.Errorf("sum of features %v, want 15", total)
+	}
+}
+
+func TestLoadCSVDatasetInference(t *testing.T) {
+	path := writeTemp(t, "mixed.csv", strings.Join([]string{
+		"n,f,b,d,c,empty,label",
+		"1,1.5,true,2024-01-02,red,,x",
+		"2,NA,false,2024-03-04,blue,NA,y",
+		"3,2,true,,red,,x",
+	}, "\n"))
+
+	opts := DefaultCSVOptions()
+	opts.HasHeader = true
+	ds, err := LoadCSVDataset(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]ColumnType{"n": Int, "f": Float, "b": Bool, "d": Time, "c": Categorical, "empty": Float, "label": Categorical}
+	for name, typ := range want {
+		c, err := ds.Column(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Type != typ {
+			t.Errorf("column %s inferred as %v, want %v", name, c.Type, typ)
+		}
+	}
+
+	d, _ := ds.Column("d")
+	if !d.IsMissing(2) {
+		t.Error("empty date cell not missing")
+	}
+
+	// The target may be listed among the columns, once
+	opts.TargetName = "label"
+	opts.Columns = []string{"label", "n"}
+	ds, err = LoadCSVDataset(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := ds.Names(); len(names) != 2 || names[0] != "label" || names[1] != "n" {
+		t.Errorf("columns %v, want [label n]", names)
+	}
+}
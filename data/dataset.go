@@ -1,19 +1,22 @@
 package data
 
 import (
-	"os"
-	"strconv"
 	"errors"
 	"math"
 	"math/rand"
-	"time"
 	"sort"
-	"encoding/csv"
 
+	"golearn-lite/core"
 	"golearn-lite/matrix"
 )
 
 func TrainTestSplit(X [][]float64, y []float64, testSize float64) (XTrain, XTest [][]float64, yTrain, yTest []float64, err error) {
+	return TrainTestSplitRand(X, y, testSize, nil)
+}
+
+// TrainTestSplitRand is TrainTestSplit with an explicit random source; the
+// same seeded rng gives the same split. A nil rng uses core.DefaultRand.
+func TrainTestSplitRand(X [][]float64, y []float64, testSize float64, rng *rand.Rand) (XTrain, XTest [][]float64, yTrain, yTest []float64, err error) {
 	if len(X) != len(y) {
 		return nil, nil, nil, nil, errors.New(" X and y must have the same number of samples")
 	}
@@ -25,8 +28,7 @@ func TrainTestSplit(X [][]float64, y []float64, testSize float64) (XTrain, XTest
 	nTest := int(float64(n) * testSize)
 
 	// Generate a list of indices and shuffle them
-	indices := rand.Perm(n)
-	rand.Seed(time.Now().UnixNano())
+	indices := core.RandOrDefault(rng).Perm(n)
 
 	// Create slices for test and train sets
 	for i, idx := range indices {
@@ -43,13 +45,18 @@ func TrainTestSplit(X [][]float64, y []float64, testSize float64) (XTrain, XTest
 }
 
 func Shuffle(X [][]float64, y []float64) ([][]float64, []float64, error) {
+	return ShuffleRand(X, y, nil)
+}
+
+// ShuffleRand is Shuffle with an explicit random source. A nil rng uses
+// core.DefaultRand.
+func ShuffleRand(X [][]float64, y []float64, rng *rand.Rand) ([][]float64, []float64, error) {
 	if len(X) != len(y) {
 		return nil, nil, errors.New(" X and y must have the same number of samples")
 	}
 
 	n := len(X)
-	rand.Seed(time.Now().UnixNano())
-	perm := rand.Perm(n)
+	perm := core.RandOrDefault(rng).Perm(n)
 
 	XShuffled := make([][]float64, n)
 	yShuffled := make([]float64, n)
@@ -105,8 +112,13 @@ func Standardize(X [][]float64) [][]float64 {
 	mean := make([]float64, cols)
 	std := make([]float64, cols)
 
-	// Compute mean
+	// Compute mean and standard deviation
 	for j := 0; j < cols; j++ {
+		for i := 0; i < rows; i++ {
+			mean[j] += X[i][j]
+		}
+		mean[j] /= float64(rows)
+
 		for i := 0; i < rows; i++ {
 			diff := X[i][j] - mean[j]
 			std[j] += diff * diff
@@ -157,7 +169,7 @@ func ToMatrix(X [][]float64) matrix.Matrix {
 }
 
 func FromMatrix(m matrix.Matrix) [][]float64 {
-	return m.Data
+	return m.ToSlices()
 }
 
 func DropNaN(X [][]float64, y []float64) ([][]float64, []float64) {
@@ -238,50 +250,3 @@ func ImputeNaN(X [][]float64, strategy string) [][]float64 {
 
 	return imputed
 }
-
-func LoadCSV(path string, hasHeader bool) ([][]float64, []float64, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	rows, err := reader.ReadAll()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	if hasHeader {
-		rows = rows[1:]
-	}
-
-	X := make([][]float64, 0, len(rows))
-	y := make([]float64, 0, len(rows))
-
-	for _, row := range rows {
-		n := len(row)
-		if n < 2 {
-			return nil, nil, errors.New("CSV row must have at least 2 columns")
-		}
-
-		xrow := make([]float64, n-1)
-		for i := 0; i < n; i++ {
-			val, err := strconv.ParseFloat(row[i], 64)
-			if err != nil {
-				return nil, nil, err
-			}
-			xrow[i] = val
-		}
-
-		target, err := strconv.ParseFloat(row[n-1], 64)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		X = append(X, xrow)
-		y = append(y, target)
-	}
-
-	return X, y, nil
-}
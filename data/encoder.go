@@ -0,0 +1,155 @@
+package data
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"golearn-lite/core"
+	"golearn-lite/matrix"
+	"golearn-lite/validation"
+)
+
+func init() {
+	gob.Register(&OneHotEncoder{})
+}
+
+// OneHotEncoder expands each feature into one indicator column per category
+// seen during Fit, in ascending order of the category value. Values not
+// seen during Fit are an error unless HandleUnknown is "ignore", in which
+// case their indicator columns are all zero.
+type OneHotEncoder struct {
+	HandleUnknown string
+	Categories    [][]float64
+}
+
+func NewOneHotEncoder() *OneHotEncoder {
+	return &OneHotEncoder{HandleUnknown: "error"}
+}
+
+func (e *OneHotEncoder) Fit(X matrix.Matrix) error {
+	if e.HandleUnknown != "error" && e.HandleUnknown != "ignore" {
+		return errors.New(`handle_unknown must be "error" or "ignore"`)
+	}
+	if err := validation.CheckX(X); err != nil {
+		return err
+	}
+
+	e.Categories = make([][]float64, X.Cols)
+	for j := range e.Categories {
+		e.Categories[j] = core.UniqueClasses(X.Col(j))
+	}
+
+	return nil
+}
+
+func (e *OneHotEncoder) Transform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := validation.CheckFitted(e.IsFitted()); err != nil {
+		return matrix.Matrix{}, err
+	}
+	if err := validation.CheckFeatures(X, len(e.Categories)); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	out := matrix.Zeros(X.Rows, e.width())
+
+	for i := 0; i < X.Rows; i++ {
+		src, dst := X.Row(i), out.Row(i)
+		offset := 0
+		for j, cats := range e.Categories {
+			k := sort.SearchFloat64s(cats, src[j])
+			if k < len(cats) && cats[k] == src[j] {
+				dst[offset+k] = 1
+			} else if e.HandleUnknown != "ignore" {
+				return matrix.Matrix{}, fmt.Errorf("unknown category %v in X[%d][%d]", src[j], i, j)
+			}
+			offset += len(cats)
+		}
+	}
+
+	return out, nil
+}
+
+func (e *OneHotEncoder) FitTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := e.Fit(X); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	return e.Transform(X)
+}
+
+// InverseTransform maps each block of indicator columns back to the
+// category with the largest entry; an all-zero block becomes NaN.
+func (e *OneHotEncoder) InverseTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := validation.CheckFitted(e.IsFitted()); err != nil {
+		return matrix.Matrix{}, err
+	}
+	if err := validation.CheckFeatures(X, e.width()); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	out := matrix.Zeros(X.Rows, len(e.Categories))
+
+	for i := 0; i < X.Rows; i++ {
+		src, dst := X.Row(i), out.Row(i)
+		offset := 0
+		for j, cats := range e.Categories {
+			best, bestVal := -1, 0.0
+			for k := range cats {
+				if v := src[offset+k]; v > bestVal {
+					best, bestVal = k, v
+				}
+			}
+
+			dst[j] = math.NaN()
+			if best >= 0 {
+				dst[j] = cats[best]
+			}
+			offset += len(cats)
+		}
+	}
+
+	return out, nil
+}
+
+// width is the number of output columns.
+func (e *OneHotEncoder) width() int {
+	n := 0
+	for _, cats := range e.Categories {
+		n += len(cats)
+	}
+
+	return n
+}
+
+func (e *OneHotEncoder) IsFitted() bool {
+	return e.Categories != nil
+}
+
+func (e *OneHotEncoder) Save(path string) error {
+	return saveGob(path, e)
+}
+
+func (e *OneHotEncoder) Load(path string) error {
+	return core.LoadGob(path, e)
+}
+
+func (e *OneHotEncoder) GetParams() map[string]interface{} {
+	return map[string]interface{}{
+		"handle_unknown": e.HandleUnknown,
+	}
+}
+
+func (e *OneHotEncoder) SetParams(params map[string]interface{}) error {
+	if v, ok := params["handle_unknown"].(string); ok {
+		e.HandleUnknown = v
+	}
+
+	return nil
+}
+
+var _ core.Transformer = (*OneHotEncoder)(nil)
+var _ core.Serializable = (*OneHotEncoder)(nil)
+var _ core.Params = (*OneHotEncoder)(nil)
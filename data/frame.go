@@ -0,0 +1,473 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"golearn-lite/matrix"
+)
+
+// ColumnType is the element type of a Dataset column.
+type ColumnType int
+
+const (
+	Float ColumnType = iota
+	Int
+	Categorical
+	Bool
+	Time
+)
+
+func (t ColumnType) String() string {
+	switch t {
+	case Float:
+		return "float"
+	case Int:
+		return "int"
+	case Categorical:
+		return "categorical"
+	case Bool:
+		return "bool"
+	case Time:
+		return "time"
+	}
+
+	return fmt.Sprintf("ColumnType(%d)", int(t))
+}
+
+// Column is a named, typed column. Only the slice matching Type holds
+// values; Missing[i] marks row i as absent whatever the stored value is.
+// Categories optionally fixes the levels of a categorical column and their
+// order, as declared by formats such as ARFF; strings outside it encode as
+// NaN rather than as any level.
+type Column struct {
+	Name       string
+	Type       ColumnType
+	Floats     []float64
+	Ints       []int64
+	Strings    []string
+	Bools      []bool
+	Times      []time.Time
+	Missing    []bool
+	Categories []string
+}
+
+// Dataset is a table of equally long, uniquely named columns.
+type Dataset struct {
+	Columns []*Column
+}
+
+// NewFloatColumn marks NaN entries as missing.
+func NewFloatColumn(name string, values []float64) *Column {
+	missing := make([]bool, len(values))
+	for i, v := range values {
+		missing[i] = math.IsNaN(v)
+	}
+
+	return &Column{Name: name, Type: Float, Floats: values, Missing: missing}
+}
+
+// The remaining constructors take an optional missing mask; nil means no
+// value is missing.
+
+func NewIntColumn(name string, values []int64, missing []bool) *Column {
+	return &Column{Name: name, Type: Int, Ints: values, Missing: maskOrNone(missing, len(values))}
+}
+
+func NewCategoricalColumn(name string, values []string, missing []bool) *Column {
+	return &Column{Name: name, Type: Categorical, Strings: values, Missing: maskOrNone(missing, len(values))}
+}
+
+func NewBoolColumn(name string, values []bool, missing []bool) *Column {
+	return &Column{Name: name, Type: Bool, Bools: values, Missing: maskOrNone(missing, len(values))}
+}
+
+func NewTimeColumn(name string, values []time.Time, missing []bool) *Column {
+	return &Column{Name: name, Type: Time, Times: values, Missing: maskOrNone(missing, len(values))}
+}
+
+func maskOrNone(missing []bool, n int) []bool {
+	if missing == nil {
+		return make([]bool, n)
+	}
+
+	return missing
+}
+
+func (c *Column) Len() int {
+	return len(c.Missing)
+}
+
+func (c *Column) IsMissing(i int) bool {
+	return c.Missing[i]
+}
+
+// Levels returns Categories if set, and otherwise the distinct non-missing
+// values of a categorical column in sorted order. Value and ToMatrix encode
+// category s as its index here. Take, Filter and Head fix the subset's
+// Categories to these levels, so subsets share the parent's encoding.
+func (c *Column) Levels() []string {
+	if c.Categories != nil {
+		return append([]string(nil), c.Categories...)
+	}
+
+	seen := make(map[string]bool)
+	var levels []string
+
+	for i, s := range c.Strings {
+		if !c.Missing[i] && !seen[s] {
+			seen[s] = true
+			levels = append(levels, s)
+		}
+	}
+	sort.Strings(levels)
+
+	return levels
+}
+
+// Value returns row i as a float64: bools become 0 or 1, times Unix
+// seconds, categories their index in Levels, and missing entries NaN, as
+// are strings absent from a fixed Categories list.
+// Categorical columns are scanned for their levels on every call; use
+// Dataset.ToMatrix to convert whole columns.
+func (c *Column) Value(i int) float64 {
+	return c.value(i, c.levelIndex())
+}
+
+func (c *Column) values() []float64 {
+	out := make([]float64, c.Len())
+	index := c.levelIndex()
+
+	for i := range out {
+		out[i] = c.value(i, index)
+	}
+
+	return out
+}
+
+func (c *Column) value(i int, index map[string]int) float64 {
+	if c.Missing[i] {
+		return math.NaN()
+	}
+
+	switch c.Type {
+	case Int:
+		return float64(c.Ints[i])
+	case Categorical:
+		k, ok := index[c.Strings[i]]
+		if !ok {
+			return math.NaN()
+		}
+		return float64(k)
+	case Bool:
+		if c.Bools[i] {
+			return 1
+		}
+		return 0
+	case Time:
+		return float64(c.Times[i].Unix())
+	}
+
+	return c.Floats[i]
+}
+
+// levelIndex maps each level to its code, or is nil for other types.
+func (c *Column) levelIndex() map[string]int {
+	if c.Type != Categorical {
+		return nil
+	}
+
+	levels := c.Levels()
+	index := make(map[string]int, len(levels))
+	for k, s := range levels {
+		index[s] = k
+	}
+
+	return index
+}
+
+// take copies the rows in idx, in that order. Categorical copies keep the
+// parent's levels even where the rows taken lack some of them.
+func (c *Column) take(idx []int) *Column {
+	out := &Column{Name: c.Name, Type: c.Type, Missing: make([]bool, len(idx)), Categories: c.Categories}
+
+	switch c.Type {
+	case Float:
+		out.Floats = make([]float64, len(idx))
+	case Int:
+		out.Ints = make([]int64, len(idx))
+	case Categorical:
+		out.Strings = make([]string, len(idx))
+		// Pin the parent's levels so a subset encodes like the whole
+		out.Categories = c.Levels()
+	case Bool:
+		out.Bools = make([]bool, len(idx))
+	case Time:
+		out.Times = make([]time.Time, len(idx))
+	}
+
+	for k, i := range idx {
+		out.Missing[k] = c.Missing[i]
+		switch c.Type {
+		case Float:
+			out.Floats[k] = c.Floats[i]
+		case Int:
+			out.Ints[k] = c.Ints[i]
+		case Categorical:
+			out.Strings[k] = c.Strings[i]
+		case Bool:
+			out.Bools[k] = c.Bools[i]
+		case Time:
+			out.Times[k] = c.Times[i]
+		}
+	}
+
+	return out
+}
+
+// valuesLen is the length of the slice that holds c's values.
+func (c *Column) valuesLen() int {
+	switch c.Type {
+	case Float:
+		return len(c.Floats)
+	case Int:
+		return len(c.Ints)
+	case Categorical:
+		return len(c.Strings)
+	case Bool:
+		return len(c.Bools)
+	case Time:
+		return len(c.Times)
+	}
+
+	return -1
+}
+
+// NewDataset checks that the columns are well formed, equally long and
+// uniquely named.
+func NewDataset(columns ...*Column) (*Dataset, error) {
+	seen := make(map[string]bool, len(columns))
+
+	for _, c := range columns {
+		if seen[c.Name] {
+			return nil, fmt.Errorf("duplicate column name %q", c.Name)
+		}
+		seen[c.Name] = true
+
+		if c.valuesLen() != len(c.Missing) {
+			return nil, fmt.Errorf("column %q: %d values but %d missing flags", c.Name, c.valuesLen(), len(c.Missing))
+		}
+		if c.Len() != columns[0].Len() {
+			return nil, fmt.Errorf("column %q has %d rows, column %q has %d", c.Name, c.Len(), columns[0].Name, columns[0].Len())
+		}
+	}
+
+	return &Dataset{Columns: columns}, nil
+}
+
+func (d *Dataset) NRows() int {
+	if len(d.Columns) == 0 {
+		return 0
+	}
+
+	return d.Columns[0].Len()
+}
+
+func (d *Dataset) NCols() int {
+	return len(d.Columns)
+}
+
+func (d *Dataset) Names() []string {
+	names := make([]string, len(d.Columns))
+	for j, c := range d.Columns {
+		names[j] = c.Name
+	}
+
+	return names
+}
+
+func (d *Dataset) Column(name string) (*Column, error) {
+	for _, c := range d.Columns {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no column named %q", name)
+}
+
+// Select returns a Dataset with the named columns in the given order. The
+// columns are shared with d, not copied.
+func (d *Dataset) Select(names ...string) (*Dataset, error) {
+	columns := make([]*Column, len(names))
+
+	for j, name := range names {
+		c, err := d.Column(name)
+		if err != nil {
+			return nil, err
+		}
+		columns[j] = c
+	}
+
+	return NewDataset(columns...)
+}
+
+// Filter returns a copy of the rows for which keep returns true.
+func (d *Dataset) Filter(keep func(row int) bool) *Dataset {
+	var idx []int
+	for i := 0; i < d.NRows(); i++ {
+		if keep(i) {
+			idx = append(idx, i)
+		}
+	}
+
+	return d.Take(idx)
+}
+
+// Take returns a copy of the rows in idx, in that order.
+func (d *Dataset) Take(idx []int) *Dataset {
+	columns := make([]*Column, len(d.Columns))
+	for j, c := range d.Columns {
+		columns[j] = c.take(idx)
+	}
+
+	return &Dataset{Columns: columns}
+}
+
+// Head returns a copy of the first n rows, or all rows if there are fewer.
+func (d *Dataset) Head(n int) *Dataset {
+	n = max(0, min(n, d.NRows()))
+
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+
+	return d.Take(idx)
+}
+
+// Describe summarizes every column as one row of a new Dataset with the
+// columns column, type, count, missing, unique, mean, std, min, 25%, 50%,
+// 75% and max. unique is only set for categorical columns and the numeric
+// statistics only for float, int and bool columns; the rest are missing.
+func (d *Dataset) Describe() *Dataset {
+	n := len(d.Columns)
+	names := make([]string, n)
+	types := make([]string, n)
+	count := make([]int64, n)
+	nMissing := make([]int64, n)
+	unique := make([]int64, n)
+	uniqueMissing := make([]bool, n)
+
+	statNames := []string{"mean", "std", "min", "25%", "50%", "75%", "max"}
+	stats := make([][]float64, len(statNames))
+	for s := range stats {
+		stats[s] = make([]float64, n)
+	}
+
+	for j, c := range d.Columns {
+		names[j], types[j] = c.Name, c.Type.String()
+
+		var present []float64
+		for i, v := range c.values() {
+			if c.Missing[i] {
+				nMissing[j]++
+			} else {
+				present = append(present, v)
+			}
+		}
+		count[j] = int64(len(present))
+
+		if c.Type == Categorical {
+			unique[j] = int64(len(c.Levels()))
+		} else {
+			uniqueMissing[j] = true
+		}
+
+		for s := range stats {
+			stats[s][j] = math.NaN()
+		}
+		if c.Type == Categorical || c.Type == Time || len(present) == 0 {
+			continue
+		}
+
+		sort.Float64s(present)
+		mean, variance := 0.0, 0.0
+		for _, v := range present {
+			mean += v
+		}
+		mean /= float64(len(present))
+		for _, v := range present {
+			variance += (v - mean) * (v - mean)
+		}
+		variance /= float64(len(present))
+
+		stats[0][j] = mean
+		stats[1][j] = math.Sqrt(variance)
+		stats[2][j] = present[0]
+		stats[3][j] = quantile(present, 25)
+		stats[4][j] = quantile(present, 50)
+		stats[5][j] = quantile(present, 75)
+		stats[6][j] = present[len(present)-1]
+	}
+
+	columns := []*Column{
+		NewCategoricalColumn("column", names, nil),
+		NewCategoricalColumn("type", types, nil),
+		NewIntColumn("count", count, nil),
+		NewIntColumn("missing", nMissing, nil),
+		NewIntColumn("unique", unique, uniqueMissing),
+	}
+	for s, name := range statNames {
+		columns = append(columns, NewFloatColumn(name, stats[s]))
+	}
+
+	return &Dataset{Columns: columns}
+}
+
+// ToMatrix converts every column except target into a feature matrix, in
+// column order, and target into y, using the encoding of Column.Value.
+// Missing entries become NaN. An empty target returns a nil y.
+func (d *Dataset) ToMatrix(target string) (matrix.Matrix, []float64, error) {
+	if len(d.Columns) == 0 {
+		return matrix.Matrix{}, nil, errors.New("dataset has no columns")
+	}
+
+	var y []float64
+	features := make([]*Column, 0, len(d.Columns))
+	for _, c := range d.Columns {
+		if target != "" && c.Name == target {
+			y = c.values()
+		} else {
+			features = append(features, c)
+		}
+	}
+	if target != "" && y == nil {
+		return matrix.Matrix{}, nil, fmt.Errorf("no column named %q", target)
+	}
+
+	X := matrix.Zeros(d.NRows(), len(features))
+	for j, c := range features {
+		for i, v := range c.values() {
+			X.Set(i, j, v)
+		}
+	}
+
+	return X, y, nil
+}
+
+// FeatureNames returns the column names ToMatrix(target) uses as features,
+// in matrix column order.
+func (d *Dataset) FeatureNames(target string) []string {
+	var names []string
+	for _, c := range d.Columns {
+		if c.Name != target {
+			names = append(names, c.Name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,99 @@
+package data
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSubsetKeepsCategoricalEncoding(t *testing.T) {
+	ds, err := NewDataset(
+		NewCategoricalColumn("c", []string{"a", "b", "b", "a"}, nil),
+		NewFloatColumn("x", []float64{1, 2, 3, 4}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	full, _, err := ds.ToMatrix("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subsets := map[string]*Dataset{
+		"Take":   ds.Take([]int{1, 2}),
+		"Filter": ds.Filter(func(row int) bool { return row == 1 || row == 2 }),
+		"Head":   ds.Take([]int{1, 2, 3}).Head(2),
+	}
+	for name, sub := range subsets {
+		X, _, err := sub.ToMatrix("")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for i := 0; i < X.Rows; i++ {
+			if got, want := X.At(i, 0), full.At(1, 0); got != want {
+				t.Errorf("%s: row %d encodes b as %v, want %v", name, i, got, want)
+			}
+		}
+	}
+}
+
+func TestCategoricalOutsideCategoriesIsNaN(t *testing.T) {
+	c := NewCategoricalColumn("c", []string{"a", "b", "zzz"}, nil)
+	c.Categories = []string{"b", "a"}
+
+	want := []float64{1, 0, math.NaN()}
+	for i, w := range want {
+		got := c.Value(i)
+		if got != w && !(math.IsNaN(got) && math.IsNaN(w)) {
+			t.Errorf("Value(%d) = %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestFloatColumnNaNIsMissing(t *testing.T) {
+	c := NewFloatColumn("x", []float64{1, math.NaN(), 3})
+
+	if c.IsMissing(0) || !c.IsMissing(1) || c.IsMissing(2) {
+		t.Errorf("missing mask = %v, want [false true false]", c.Missing)
+	}
+}
+
+func TestToMatrixTarget(t *testing.T) {
+	ds, err := NewDataset(
+		NewFloatColumn("x", []float64{1, 2}),
+		NewBoolColumn("flag", []bool{true, false}, nil),
+		NewIntColumn("y", []int64{7, 9}, nil),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	X, y, err := ds.ToMatrix("y")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if X.Rows != 2 || X.Cols != 2 {
+		t.Fatalf("X is %dx%d, want 2x2", X.Rows, X.Cols)
+	}
+	if X.At(0, 1) != 1 || X.At(1, 1) != 0 {
+		t.Errorf("bool column encoded as %v, %v", X.At(0, 1), X.At(1, 1))
+	}
+	if y[0] != 7 || y[1] != 9 {
+		t.Errorf("y = %v, want [7 9]", y)
+	}
+	if _, _, err := ds.ToMatrix("missing"); err == nil {
+		t.Error("expected an error for an unknown target")
+	}
+}
+
+func TestNewDatasetRejectsMismatchedColumns(t *testing.T) {
+	_, err := NewDataset(NewFloatColumn("x", []float64{1, 2}), NewFloatColumn("y", []float64{1}))
+	if err == nil {
+		t.Error("expected an error for columns of different lengths")
+	}
+
+	_, err = NewDataset(NewFloatColumn("x", []float64{1}), NewFloatColumn("x", []float64{2}))
+	if err == nil {
+		t.Error("expected an error for duplicate names")
+	}
+}
@@ -0,0 +1,136 @@
+package data
+
+import (
+	"encoding/gob"
+	"fmt"
+	"math"
+	"sort"
+
+	"golearn-lite/core"
+	"golearn-lite/matrix"
+	"golearn-lite/validation"
+)
+
+func init() {
+	gob.Register(&Imputer{})
+}
+
+// Imputer replaces NaN with a per-feature statistic learned during Fit. It
+// is the Transformer form of ImputeNaN and supports the same strategies:
+// "mean", "median", or anything else for zero. Features that are entirely
+// NaN during Fit are filled with zero.
+type Imputer struct {
+	Strategy   string
+	Statistics []float64
+}
+
+func NewImputer(strategy string) *Imputer {
+	return &Imputer{Strategy: strategy}
+}
+
+func (im *Imputer) Fit(X matrix.Matrix) error {
+	if err := validation.CheckMinSamples(X.Rows, 1); err != nil {
+		return err
+	}
+	if X.Cols == 0 {
+		return fmt.Errorf("%w: X has no features", validation.ErrShapeMismatch)
+	}
+
+	im.Statistics = make([]float64, X.Cols)
+
+	for j := range im.Statistics {
+		col := make([]float64, 0, X.Rows)
+		for _, v := range X.Col(j) {
+			if math.IsInf(v, 0) {
+				return fmt.Errorf("%w: X[:, %d] contains Inf", validation.ErrNonFinite, j)
+			}
+			if !math.IsNaN(v) {
+				col = append(col, v)
+			}
+		}
+
+		if len(col) == 0 {
+			continue
+		}
+
+		switch im.Strategy {
+		case "mean":
+			sum := 0.0
+			for _, v := range col {
+				sum += v
+			}
+			im.Statistics[j] = sum / float64(len(col))
+		case "median":
+			sort.Float64s(col)
+			im.Statistics[j] = quantile(col, 50)
+		}
+	}
+
+	return nil
+}
+
+func (im *Imputer) Transform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := checkTransform(X, im.Statistics); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	out := X.Copy()
+	for i := 0; i < out.Rows; i++ {
+		row := out.Row(i)
+		for j, v := range row {
+			if math.IsNaN(v) {
+				row[j] = im.Statistics[j]
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func (im *Imputer) FitTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := im.Fit(X); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	return im.Transform(X)
+}
+
+// InverseTransform returns a copy of X: which entries were missing is not
+// recorded, so imputation cannot be undone.
+func (im *Imputer) InverseTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := checkTransform(X, im.Statistics); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	return X.Copy(), nil
+}
+
+func (im *Imputer) IsFitted() bool {
+	return im.Statistics != nil
+}
+
+func (im *Imputer) Save(path string) error {
+	return saveGob(path, im)
+}
+
+func (im *Imputer) Load(path string) error {
+	return core.LoadGob(path, im)
+}
+
+func (im *Imputer) GetParams() map[string]interface{} {
+	return map[string]interface{}{
+		"strategy": im.Strategy,
+	}
+}
+
+func (im *Imputer) SetParams(params map[string]interface{}) error {
+	if v, ok := params["strategy"].(string); ok {
+		im.Strategy = v
+	}
+
+	return nil
+}
+
+var _ core.Transformer = (*Imputer)(nil)
+var _ core.Serializable = (*Imputer)(nil)
+var _ core.Params = (*Imputer)(nil)
@@ -0,0 +1,152 @@
+package data
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONLField loads the value at a dotted key path, such as "user.id", into
+// the column Name.
+type JSONLField struct {
+	Name string
+	Path string
+}
+
+// LoadJSONL reads a file with one JSON object per line into a Dataset.
+// Without fields every scalar leaf becomes a column named by its dotted
+// path, in sorted order; arrays are kept as JSON text. Missing keys and
+// nulls are missing values, and column types are inferred as in
+// LoadCSVDataset.
+func LoadJSONL(path string, fields ...JSONLField) (*Dataset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []map[string]interface{}
+	br := bufio.NewReader(f)
+
+	for lineNo := 1; ; lineNo++ {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if line == "" && err == io.EOF {
+			break
+		}
+
+		if strings.TrimSpace(line) != "" {
+			dec := json.NewDecoder(strings.NewReader(line))
+			dec.UseNumber()
+
+			var record map[string]interface{}
+			if err := dec.Decode(&record); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			if record == nil {
+				return nil, fmt.Errorf("line %d: expected a JSON object", lineNo)
+			}
+			records = append(records, record)
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	if len(fields) == 0 {
+		fields = discoverFields(records)
+	}
+
+	columns := make([]*Column, len(fields))
+	for k, field := range fields {
+		cells := make([]string, len(records))
+		missing := make([]bool, len(records))
+
+		for i, record := range records {
+			v, ok := lookupPath(record, field.Path)
+			if !ok || v == nil {
+				missing[i] = true
+				continue
+			}
+			if cells[i], err = jsonCell(v); err != nil {
+				return nil, fmt.Errorf("record %d, %s: %w", i+1, field.Path, err)
+			}
+		}
+
+		columns[k] = inferColumn(field.Name, cells, missing)
+	}
+
+	return NewDataset(columns...)
+}
+
+// lookupPath follows a dotted path through nested objects.
+func lookupPath(record map[string]interface{}, path string) (interface{}, bool) {
+	var v interface{} = record
+
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// discoverFields lists the dotted paths of every non-object value in any
+// record.
+func discoverFields(records []map[string]interface{}) []JSONLField {
+	seen := make(map[string]bool)
+
+	var walk func(prefix string, obj map[string]interface{})
+	walk = func(prefix string, obj map[string]interface{}) {
+		for key, v := range obj {
+			if nested, ok := v.(map[string]interface{}); ok {
+				walk(prefix+key+".", nested)
+			} else {
+				seen[prefix+key] = true
+			}
+		}
+	}
+	for _, record := range records {
+		walk("", record)
+	}
+
+	paths := make([]string, 0, len(seen))
+	for p := range seen {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	fields := make([]JSONLField, len(paths))
+	for k, p := range paths {
+		fields[k] = JSONLField{Name: p, Path: p}
+	}
+
+	return fields
+}
+
+func jsonCell(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case string:
+		return v, nil
+	}
+
+	b, err := json.Marshal(v)
+	return string(b), err
+}
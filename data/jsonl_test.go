@@ -0,0 +1,84 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+const jsonlInput = `{"id": 1, "user": {"name": "ann", "score": 1.5}, "tags": ["a", "b"]}
+
+{"id": 2, "user": {"name": null, "score": 2}, "active": true}
+{"id": 3, "user": {"name": "bob"}, "active": false}
+`
+
+func TestLoadJSONLDiscoversFields(t *testing.T) {
+	ds, err := LoadJSONL(writeTemp(t, "in.jsonl", jsonlInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"active", "id", "tags", "user.name", "user.score"}
+	if got := ds.Names(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("columns %v, want %v", got, want)
+	}
+	if ds.NRows() != 3 {
+		t.Fatalf("%d rows, want 3", ds.NRows())
+	}
+
+	types := map[string]ColumnType{"active": Bool, "id": Int, "tags": Categorical, "user.name": Categorical, "user.score": Float}
+	for name, typ := range types {
+		c, _ := ds.Column(name)
+		if c.Type != typ {
+			t.Errorf("%s is %v, want %v", name, c.Type, typ)
+		}
+	}
+
+	active, _ := ds.Column("active")
+	if !active.IsMissing(0) || !active.Bools[1] || active.Bools[2] {
+		t.Errorf("active = %v, missing %v", active.Bools, active.Missing)
+	}
+	tags, _ := ds.Column("tags")
+	if tags.Strings[0] != `["a","b"]` || !tags.IsMissing(1) {
+		t.Errorf("tags = %q, missing %v", tags.Strings, tags.Missing)
+	}
+	name, _ := ds.Column("user.name")
+	if name.Strings[0] != "ann" || !name.IsMissing(1) || name.Strings[2] != "bob" {
+		t.Errorf("user.name = %q, missing %v", name.Strings, name.Missing)
+	}
+	score, _ := ds.Column("user.score")
+	if score.Floats[0] != 1.5 || score.Floats[1] != 2 || !score.IsMissing(2) {
+		t.Errorf("user.score = %v, missing %v", score.Floats, score.Missing)
+	}
+}
+
+func TestLoadJSONLFields(t *testing.T) {
+	ds, err := LoadJSONL(writeTemp(t, "in.jsonl", jsonlInput),
+		JSONLField{Name: "score", Path: "user.score"},
+		JSONLField{Name: "nested", Path: "id.deeper"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ds.Names(); !reflect.DeepEqual(got, []string{"score", "nested"}) {
+		t.Fatalf("columns %v", got)
+	}
+	nested, _ := ds.Column("nested")
+	for i := 0; i < ds.NRows(); i++ {
+		if !nested.IsMissing(i) {
+			t.Errorf("row %d: a path through a scalar should be missing", i)
+		}
+	}
+}
+
+func TestLoadJSONLErrors(t *testing.T) {
+	for name, input := range map[string]string{
+		"not an object": "{\"a\": 1}\n[1, 2]\n",
+		"null line":     "null\n",
+		"bad json":      "{\"a\": \n",
+	} {
+		if _, err := LoadJSONL(writeTemp(t, "in.jsonl", input)); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
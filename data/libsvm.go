@@ -0,0 +1,244 @@
+package data
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golearn-lite/matrix"
+)
+
+// LibSVMOptions configures LoadLibSVM and SaveLibSVM.
+type LibSVMOptions struct {
+	ZeroBased  bool // feature indices start at 0 instead of 1
+	Multilabel bool // the label field is a comma-separated, possibly empty, list
+	NFeatures  int  // loading only: column count, 0 to infer it from the largest index
+}
+
+// LibSVMData is the content of a LibSVM/SVMlight file. Exactly one of Y and
+// Labels is set, depending on LibSVMOptions.Multilabel. QID is nil unless
+// the file has qid fields; rows without one get 0.
+type LibSVMData struct {
+	X      *matrix.Sparse
+	Y      []float64
+	Labels [][]float64
+	QID    []int64
+}
+
+// Dense returns X as a dense matrix.
+func (d *LibSVMData) Dense() matrix.Matrix {
+	return d.X.ToDense()
+}
+
+// LoadLibSVM reads a file of lines "label [qid:n] index:value ..." into a
+// CSR matrix. Text after '#' is a comment.
+func LoadLibSVM(path string, opts LibSVMOptions) (*LibSVMData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadLibSVM(f, opts)
+}
+
+func ReadLibSVM(r io.Reader, opts LibSVMOptions) (*LibSVMData, error) {
+	br := bufio.NewReader(r)
+	d := &LibSVMData{}
+	var ri, ci []int
+	var values []float64
+	var qids []int64
+	hasQID := false
+	nCols := 0
+	row := 0
+
+	for lineNo := 1; ; lineNo++ {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if line == "" && err == io.EOF {
+			break
+		}
+
+		if k := strings.IndexByte(line, '#'); k >= 0 {
+			line = line[:k]
+		}
+		tokens := strings.Fields(line)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		// Labels
+		if opts.Multilabel {
+			var labels []float64
+			if !strings.Contains(tokens[0], ":") {
+				for _, s := range strings.Split(tokens[0], ",") {
+					if s == "" {
+						continue
+					}
+					v, err := strconv.ParseFloat(s, 64)
+					if err != nil {
+						return nil, fmt.Errorf("line %d: invalid label %q", lineNo, s)
+					}
+					labels = append(labels, v)
+				}
+				tokens = tokens[1:]
+			}
+			d.Labels = append(d.Labels, labels)
+		} else {
+			v, err := strconv.ParseFloat(tokens[0], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid label %q", lineNo, tokens[0])
+			}
+			d.Y = append(d.Y, v)
+			tokens = tokens[1:]
+		}
+
+		// Query id
+		qid := int64(0)
+		if len(tokens) > 0 && strings.HasPrefix(tokens[0], "qid:") {
+			qid, err = strconv.ParseInt(tokens[0][len("qid:"):], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %q", lineNo, tokens[0])
+			}
+			hasQID = true
+			tokens = tokens[1:]
+		}
+		qids = append(qids, qid)
+
+		// Features
+		for _, tok := range tokens {
+			idxStr, valStr, ok := strings.Cut(tok, ":")
+			if !ok {
+				return nil, fmt.Errorf("line %d: %q is not index:value", lineNo, tok)
+			}
+			j, err := strconv.Atoi(idxStr)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid feature index %q", lineNo, idxStr)
+			}
+			if !opts.ZeroBased {
+				j--
+			}
+			if j < 0 {
+				return nil, fmt.Errorf("line %d: feature index %s out of range", lineNo, idxStr)
+			}
+			v, err := strconv.ParseFloat(valStr, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid feature value %q", lineNo, valStr)
+			}
+
+			ri, ci, values = append(ri, row), append(ci, j), append(values, v)
+			nCols = max(nCols, j+1)
+		}
+		row++
+	}
+
+	if opts.NFeatures > 0 {
+		if nCols > opts.NFeatures {
+			return nil, fmt.Errorf("feature index %d exceeds NFeatures %d", nCols-1, opts.NFeatures)
+		}
+		nCols = opts.NFeatures
+	}
+
+	X, err := matrix.NewSparseFromTriplets(matrix.CSR, row, nCols, ri, ci, values)
+	if err != nil {
+		return nil, err
+	}
+	d.X = X
+	if hasQID {
+		d.QID = qids
+	}
+	if opts.Multilabel && d.Labels == nil {
+		d.Labels = [][]float64{}
+	}
+	if !opts.Multilabel && d.Y == nil {
+		d.Y = []float64{}
+	}
+
+	return d, nil
+}
+
+// SaveLibSVM writes d in LibSVM format, omitting zero entries.
+func SaveLibSVM(path string, d *LibSVMData, opts LibSVMOptions) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteLibSVM(f, d, opts); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// WriteLibSVM writes d in LibSVM format. In multilabel mode a row with no
+// labels, qid or features is written as a lone "," so that it is read back
+// as an empty row instead of being skipped as a blank line.
+func WriteLibSVM(w io.Writer, d *LibSVMData, opts LibSVMOptions) error {
+	rows, _ := d.X.Dims()
+
+	if opts.Multilabel && len(d.Labels) != rows {
+		return fmt.Errorf("%d rows but %d label lists", rows, len(d.Labels))
+	}
+	if !opts.Multilabel && len(d.Y) != rows {
+		return fmt.Errorf("%d rows but %d labels", rows, len(d.Y))
+	}
+	if d.QID != nil && len(d.QID) != rows {
+		return errors.New("QID must have one entry per row")
+	}
+
+	offset := 1
+	if opts.ZeroBased {
+		offset = 0
+	}
+
+	X := d.X.ToCSR()
+	bw := bufio.NewWriter(w)
+	var b []byte
+
+	for i := 0; i < rows; i++ {
+		b = b[:0]
+		if opts.Multilabel {
+			for k, v := range d.Labels[i] {
+				if k > 0 {
+					b = append(b, ',')
+				}
+				b = strconv.AppendFloat(b, v, 'g', -1, 64)
+			}
+		} else {
+			b = strconv.AppendFloat(b, d.Y[i], 'g', -1, 64)
+		}
+
+		if d.QID != nil {
+			b = append(b, " qid:"...)
+			b = strconv.AppendInt(b, d.QID[i], 10)
+		}
+
+		X.DoRowNonZero(i, func(j int, v float64) {
+			if v == 0 {
+				return
+			}
+			b = append(b, ' ')
+			b = strconv.AppendInt(b, int64(j+offset), 10)
+			b = append(b, ':')
+			b = strconv.AppendFloat(b, v, 'g', -1, 64)
+		})
+		if len(b) == 0 {
+			b = append(b, ',')
+		}
+		b = append(b, '\n')
+
+		if _, err := bw.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,121 @@
+package data
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"golearn-lite/matrix"
+)
+
+func TestReadLibSVM(t *testing.T) {
+	input := strings.Join([]string{
+		"# header comment",
+		"1 qid:3 1:0.5 3:2 # trailing comment",
+		"",
+		"-1 qid:4 2:1.5",
+	}, "\n")
+
+	d, err := ReadLibSVM(strings.NewReader(input), LibSVMOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := matrix.New([][]float64{{0.5, 0, 2}, {0, 1.5, 0}})
+	X := d.Dense()
+	if X.Rows != 2 || X.Cols != 3 {
+		t.Fatalf("X is %dx%d, want 2x3", X.Rows, X.Cols)
+	}
+	for i := range want.Data {
+		if X.Data[i] != want.Data[i] {
+			t.Fatalf("X = %v, want %v", X.Data, want.Data)
+		}
+	}
+	if d.Y[0] != 1 || d.Y[1] != -1 {
+		t.Errorf("Y = %v", d.Y)
+	}
+	if len(d.QID) != 2 || d.QID[0] != 3 || d.QID[1] != 4 {
+		t.Errorf("QID = %v", d.QID)
+	}
+}
+
+func TestReadLibSVMOptions(t *testing.T) {
+	d, err := ReadLibSVM(strings.NewReader("0 0:1\n1 1:2\n"), LibSVMOptions{ZeroBased: true, NFeatures: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows, cols := d.X.Dims(); rows != 2 || cols != 4 {
+		t.Errorf("X is %dx%d, want 2x4", rows, cols)
+	}
+	if d.QID != nil {
+		t.Errorf("QID = %v, want nil without qid fields", d.QID)
+	}
+
+	bad := map[string]string{
+		"zero index one-based": "1 0:1\n",
+		"bad label":            "x 1:1\n",
+		"bad pair":             "1 1-1\n",
+		"too many features":    "1 9:1\n",
+	}
+	for name, input := range bad {
+		if _, err := ReadLibSVM(strings.NewReader(input), LibSVMOptions{NFeatures: 4}); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+func TestLibSVMRoundTrip(t *testing.T) {
+	X, err := matrix.NewSparseFromTriplets(matrix.CSR, 4, 3, []int{0, 0, 2, 3}, []int{0, 2, 1, 2}, []float64{1, -2.5, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data LibSVMData
+		opts LibSVMOptions
+	}{
+		{"labels", LibSVMData{X: X, Y: []float64{1, 0, 1, 2}}, LibSVMOptions{}},
+		{"zero-based qid", LibSVMData{X: X, Y: []float64{1, 0, 1, 2}, QID: []int64{1, 1, 2, 2}}, LibSVMOptions{ZeroBased: true}},
+		// Row 1 has no labels, qid or features and must survive
+		{"multilabel", LibSVMData{X: X, Labels: [][]float64{{1, 2}, nil, {}, {3}}}, LibSVMOptions{Multilabel: true}},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := WriteLibSVM(&buf, &tt.data, tt.opts); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		tt.opts.NFeatures = 3
+		got, err := ReadLibSVM(&buf, tt.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		if rows, _ := got.X.Dims(); rows != 4 {
+			t.Fatalf("%s: %d rows, want 4", tt.name, rows)
+		}
+		want, dense := tt.data.Dense(), got.Dense()
+		for i := range want.Data {
+			if dense.Data[i] != want.Data[i] {
+				t.Fatalf("%s: X = %v, want %v", tt.name, dense.Data, want.Data)
+			}
+		}
+		for i, y := range tt.data.Y {
+			if got.Y[i] != y {
+				t.Errorf("%s: Y = %v, want %v", tt.name, got.Y, tt.data.Y)
+			}
+		}
+		for i, q := range tt.data.QID {
+			if got.QID[i] != q {
+				t.Errorf("%s: QID = %v, want %v", tt.name, got.QID, tt.data.QID)
+			}
+		}
+		for i, labels := range tt.data.Labels {
+			if len(got.Labels[i]) != len(labels) {
+				t.Errorf("%s: row %d labels %v, want %v", tt.name, i, got.Labels[i], labels)
+			}
+		}
+	}
+}
@@ -0,0 +1,449 @@
+package data
+
+import (
+	"encoding/gob"
+	"errors"
+	"math"
+	"os"
+	"sort"
+
+	"golearn-lite/core"
+	"golearn-lite/matrix"
+	"golearn-lite/validation"
+)
+
+func init() {
+	gob.Register(&MinMaxScaler{})
+	gob.Register(&StandardScaler{})
+	gob.Register(&RobustScaler{})
+	gob.Register(&MaxAbsScaler{})
+}
+
+// MinMaxScaler maps each feature linearly onto [FeatureMin, FeatureMax]
+// using the minimum and maximum seen during Fit.
+type MinMaxScaler struct {
+	FeatureMin float64
+	FeatureMax float64
+	DataMin    []float64
+	DataMax    []float64
+}
+
+// StandardScaler removes the mean and divides by the population standard
+// deviation of each feature.
+type StandardScaler struct {
+	WithMean bool
+	WithStd  bool
+	Mean     []float64
+	Scale    []float64
+}
+
+// RobustScaler removes the median and divides by the inter-quantile range,
+// which keeps outliers from dominating the statistics. Quantiles are given
+// in percent.
+type RobustScaler struct {
+	WithCentering bool
+	WithScaling   bool
+	QuantileMin   float64
+	QuantileMax   float64
+	Center        []float64
+	Scale         []float64
+}
+
+// MaxAbsScaler divides each feature by its maximum absolute value, mapping
+// data into [-1, 1] without shifting it, so zeros stay zero.
+type MaxAbsScaler struct {
+	MaxAbs []float64
+}
+
+func NewMinMaxScaler() *MinMaxScaler {
+	return &MinMaxScaler{FeatureMin: 0, FeatureMax: 1}
+}
+
+func NewStandardScaler() *StandardScaler {
+	return &StandardScaler{WithMean: true, WithStd: true}
+}
+
+func NewRobustScaler() *RobustScaler {
+	return &RobustScaler{WithCentering: true, WithScaling: true, QuantileMin: 25, QuantileMax: 75}
+}
+
+func NewMaxAbsScaler() *MaxAbsScaler {
+	return &MaxAbsScaler{}
+}
+
+// MinMaxScaler
+
+func (s *MinMaxScaler) Fit(X matrix.Matrix) error {
+	if s.FeatureMin >= s.FeatureMax {
+		return errors.New("feature range minimum must be below its maximum")
+	}
+	if err := validation.CheckX(X); err != nil {
+		return err
+	}
+
+	s.DataMin, _ = X.MinAxis(matrix.AxisRows)
+	s.DataMax, _ = X.MaxAxis(matrix.AxisRows)
+
+	return nil
+}
+
+func (s *MinMaxScaler) Transform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := checkTransform(X, s.DataMin); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	scale, offset := s.affine()
+	scaled, _ := X.MulRowVector(scale)
+	return scaled.AddRowVector(offset)
+}
+
+func (s *MinMaxScaler) FitTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := s.Fit(X); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	return s.Transform(X)
+}
+
+func (s *MinMaxScaler) InverseTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := checkTransform(X, s.DataMin); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	scale, offset := s.affine()
+	shifted, _ := X.SubRowVector(offset)
+	return shifted.DivRowVector(scale)
+}
+
+// affine returns scale and offset with Transform(x) = x*scale + offset.
+// Constant features get a unit data range so they map to FeatureMin.
+func (s *MinMaxScaler) affine() (scale, offset []float64) {
+	scale = make([]float64, len(s.DataMin))
+	offset = make([]float64, len(s.DataMin))
+
+	for j := range scale {
+		dataRange := s.DataMax[j] - s.DataMin[j]
+		if dataRange == 0 {
+			dataRange = 1
+		}
+		scale[j] = (s.FeatureMax - s.FeatureMin) / dataRange
+		offset[j] = s.FeatureMin - s.DataMin[j]*scale[j]
+	}
+
+	return scale, offset
+}
+
+func (s *MinMaxScaler) IsFitted() bool {
+	return s.DataMin != nil
+}
+
+func (s *MinMaxScaler) Save(path string) error {
+	return saveGob(path, s)
+}
+
+func (s *MinMaxScaler) Load(path string) error {
+	return core.LoadGob(path, s)
+}
+
+func (s *MinMaxScaler) GetParams() map[string]interface{} {
+	return map[string]interface{}{
+		"feature_min": s.FeatureMin,
+		"feature_max": s.FeatureMax,
+	}
+}
+
+func (s *MinMaxScaler) SetParams(params map[string]interface{}) error {
+	if v, ok := params["feature_min"].(float64); ok {
+		s.FeatureMin = v
+	}
+	if v, ok := params["feature_max"].(float64); ok {
+		s.FeatureMax = v
+	}
+
+	return nil
+}
+
+// StandardScaler
+
+func (s *StandardScaler) Fit(X matrix.Matrix) error {
+	if err := validation.CheckX(X); err != nil {
+		return err
+	}
+
+	s.Mean = make([]float64, X.Cols)
+	s.Scale = make([]float64, X.Cols)
+	for j := range s.Scale {
+		s.Scale[j] = 1
+	}
+
+	if s.WithMean {
+		s.Mean, _ = X.MeanAxis(matrix.AxisRows)
+	}
+	if s.WithStd {
+		vars, _ := X.VarAxis(matrix.AxisRows)
+		for j, v := range vars {
+			if v > 0 {
+				s.Scale[j] = math.Sqrt(v)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *StandardScaler) Transform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := checkTransform(X, s.Mean); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	centered, _ := X.SubRowVector(s.Mean)
+	return centered.DivRowVector(s.Scale)
+}
+
+func (s *StandardScaler) FitTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := s.Fit(X); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	return s.Transform(X)
+}
+
+func (s *StandardScaler) InverseTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := checkTransform(X, s.Mean); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	scaled, _ := X.MulRowVector(s.Scale)
+	return scaled.AddRowVector(s.Mean)
+}
+
+func (s *StandardScaler) IsFitted() bool {
+	return s.Mean != nil
+}
+
+func (s *StandardScaler) Save(path string) error {
+	return saveGob(path, s)
+}
+
+func (s *StandardScaler) Load(path string) error {
+	return core.LoadGob(path, s)
+}
+
+func (s *StandardScaler) GetParams() map[string]interface{} {
+	return map[string]interface{}{
+		"with_mean": s.WithMean,
+		"with_std":  s.WithStd,
+	}
+}
+
+func (s *StandardScaler) SetParams(params map[string]interface{}) error {
+	if v, ok := params["with_mean"].(bool); ok {
+		s.WithMean = v
+	}
+	if v, ok := params["with_std"].(bool); ok {
+		s.WithStd = v
+	}
+
+	return nil
+}
+
+// RobustScaler
+
+func (s *RobustScaler) Fit(X matrix.Matrix) error {
+	if s.QuantileMin < 0 || s.QuantileMax > 100 || s.QuantileMin >= s.QuantileMax {
+		return errors.New("quantile range must satisfy 0 <= min < max <= 100")
+	}
+	if err := validation.CheckX(X); err != nil {
+		return err
+	}
+
+	s.Center = make([]float64, X.Cols)
+	s.Scale = make([]float64, X.Cols)
+
+	for j := 0; j < X.Cols; j++ {
+		col := X.Col(j)
+		sort.Float64s(col)
+
+		if s.WithCentering {
+			s.Center[j] = quantile(col, 50)
+		}
+
+		s.Scale[j] = 1
+		if s.WithScaling {
+			if iqr := quantile(col, s.QuantileMax) - quantile(col, s.QuantileMin); iqr > 0 {
+				s.Scale[j] = iqr
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *RobustScaler) Transform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := checkTransform(X, s.Center); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	centered, _ := X.SubRowVector(s.Center)
+	return centered.DivRowVector(s.Scale)
+}
+
+func (s *RobustScaler) FitTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := s.Fit(X); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	return s.Transform(X)
+}
+
+func (s *RobustScaler) InverseTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := checkTransform(X, s.Center); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	scaled, _ := X.MulRowVector(s.Scale)
+	return scaled.AddRowVector(s.Center)
+}
+
+func (s *RobustScaler) IsFitted() bool {
+	return s.Center != nil
+}
+
+func (s *RobustScaler) Save(path string) error {
+	return saveGob(path, s)
+}
+
+func (s *RobustScaler) Load(path string) error {
+	return core.LoadGob(path, s)
+}
+
+func (s *RobustScaler) GetParams() map[string]interface{} {
+	return map[string]interface{}{
+		"with_centering": s.WithCentering,
+		"with_scaling":   s.WithScaling,
+		"quantile_min":   s.QuantileMin,
+		"quantile_max":   s.QuantileMax,
+	}
+}
+
+func (s *RobustScaler) SetParams(params map[string]interface{}) error {
+	if v, ok := params["with_centering"].(bool); ok {
+		s.WithCentering = v
+	}
+	if v, ok := params["with_scaling"].(bool); ok {
+		s.WithScaling = v
+	}
+	if v, ok := params["quantile_min"].(float64); ok {
+		s.QuantileMin = v
+	}
+	if v, ok := params["quantile_max"].(float64); ok {
+		s.QuantileMax = v
+	}
+
+	return nil
+}
+
+// MaxAbsScaler
+
+func (s *MaxAbsScaler) Fit(X matrix.Matrix) error {
+	if err := validation.CheckX(X); err != nil {
+		return err
+	}
+
+	abs := X.Apply(math.Abs)
+	s.MaxAbs, _ = abs.MaxAxis(matrix.AxisRows)
+	for j, v := range s.MaxAbs {
+		if v == 0 {
+			s.MaxAbs[j] = 1
+		}
+	}
+
+	return nil
+}
+
+func (s *MaxAbsScaler) Transform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := checkTransform(X, s.MaxAbs); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	return X.DivRowVector(s.MaxAbs)
+}
+
+func (s *MaxAbsScaler) FitTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := s.Fit(X); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	return s.Transform(X)
+}
+
+func (s *MaxAbsScaler) InverseTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := checkTransform(X, s.MaxAbs); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	return X.MulRowVector(s.MaxAbs)
+}
+
+func (s *MaxAbsScaler) IsFitted() bool {
+	return s.MaxAbs != nil
+}
+
+func (s *MaxAbsScaler) Save(path string) error {
+	return saveGob(path, s)
+}
+
+func (s *MaxAbsScaler) Load(path string) error {
+	return core.LoadGob(path, s)
+}
+
+func (s *MaxAbsScaler) GetParams() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+func (s *MaxAbsScaler) SetParams(map[string]interface{}) error {
+	return nil
+}
+
+// checkTransform rejects data when the scaler is unfitted (stats is nil) or
+// the feature count differs from Fit.
+func checkTransform(X matrix.Matrix, stats []float64) error {
+	if err := validation.CheckFitted(stats != nil); err != nil {
+		return err
+	}
+
+	return validation.CheckFeatures(X, len(stats))
+}
+
+// quantile interpolates linearly between the closest ranks of a sorted
+// slice; q is in percent.
+func quantile(sorted []float64, q float64) float64 {
+	pos := q / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
+
+func saveGob(path string, v interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewEncoder(f).Encode(v)
+}
+
+var _ core.Transformer = (*MinMaxScaler)(nil)
+var _ core.Transformer = (*StandardScaler)(nil)
+var _ core.Transformer = (*RobustScaler)(nil)
+var _ core.Transformer = (*MaxAbsScaler)(nil)
+var _ core.Serializable = (*MinMaxScaler)(nil)
+var _ core.Serializable = (*StandardScaler)(nil)
+var _ core.Serializable = (*RobustScaler)(nil)
+var _ core.Serializable = (*MaxAbsScaler)(nil)
+var _ core.Params = (*MinMaxScaler)(nil)
+var _ core.Params = (*StandardScaler)(nil)
+var _ core.Params = (*RobustScaler)(nil)
+var _ core.Params = (*MaxAbsScaler)(nil)
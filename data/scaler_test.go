@@ -0,0 +1,59 @@
+package data
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"golearn-lite/core"
+	"golearn-lite/matrix"
+)
+
+func TestScalerRoundTrip(t *testing.T) {
+	X := matrix.New([][]float64{{1, -2}, {3, 0}, {5, 8}, {-1, 4}})
+
+	scalers := map[string]core.Transformer{
+		"minmax":   NewMinMaxScaler(),
+		"standard": NewStandardScaler(),
+		"robust":   NewRobustScaler(),
+		"maxabs":   NewMaxAbsScaler(),
+	}
+	for name, s := range scalers {
+		out, err := s.FitTransform(X)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		back, err := s.InverseTransform(out)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for i := 0; i < X.Rows; i++ {
+			for j := 0; j < X.Cols; j++ {
+				if math.Abs(back.At(i, j)-X.At(i, j)) > 1e-9 {
+					t.Fatalf("%s: inverse (%d, %d) = %v, want %v", name, i, j, back.At(i, j), X.At(i, j))
+				}
+			}
+		}
+	}
+}
+
+func TestStandardScalerLoadKeepsFalseFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scaler.gob")
+
+	s := NewStandardScaler()
+	s.WithMean = false
+	if err := s.Fit(matrix.New([][]float64{{1, 2}, {3, 5}})); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewStandardScaler()
+	if err := loaded.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.WithMean || !loaded.WithStd {
+		t.Errorf("loaded WithMean = %v, WithStd = %v; want false, true", loaded.WithMean, loaded.WithStd)
+	}
+}
@@ -0,0 +1,202 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"golearn-lite/core"
+	"golearn-lite/matrix"
+	"golearn-lite/validation"
+)
+
+// StratifiedTrainTestSplit holds out testSize of the samples while keeping
+// each class's share of the test set as close as possible to its share of
+// y. Every class needs at least two samples so it can appear on both
+// sides, and the test set must be large enough to hold one sample per
+// class. A nil rng uses core.DefaultRand.
+func StratifiedTrainTestSplit(X matrix.Matrix, y []float64, testSize float64, rng *rand.Rand) (XTrain, XTest matrix.Matrix, yTrain, yTest []float64, err error) {
+	if err := checkSplit(X, y, testSize); err != nil {
+		return matrix.Matrix{}, matrix.Matrix{}, nil, nil, err
+	}
+	rng = core.RandOrDefault(rng)
+
+	byClass := make(map[float64][]int)
+	for i, label := range y {
+		byClass[label] = append(byClass[label], i)
+	}
+	classes := core.UniqueClasses(y)
+
+	n := len(y)
+	nTest := int(float64(n) * testSize)
+	if nTest < len(classes) || n-nTest < len(classes) {
+		return matrix.Matrix{}, matrix.Matrix{}, nil, nil, fmt.Errorf("test size %d and train size %d must each be at least the number of classes, %d", nTest, n-nTest, len(classes))
+	}
+
+	counts := make([]int, len(classes))
+	for k, c := range classes {
+		counts[k] = len(byClass[c])
+		if counts[k] < 2 {
+			return matrix.Matrix{}, matrix.Matrix{}, nil, nil, fmt.Errorf("class %v has only %d sample; stratification needs at least 2", c, counts[k])
+		}
+	}
+
+	var train, test []int
+	for k, quota := range allocate(counts, nTest) {
+		idx := byClass[classes[k]]
+		rng.Shuffle(len(idx), func(a, b int) { idx[a], idx[b] = idx[b], idx[a] })
+		test = append(test, idx[:quota]...)
+		train = append(train, idx[quota:]...)
+	}
+
+	// Interleave the classes so neither side is ordered by label
+	shuffleInts(train, rng)
+	shuffleInts(test, rng)
+
+	XTrain, yTrain = takeRows(X, y, train)
+	XTest, yTest = takeRows(X, y, test)
+
+	return XTrain, XTest, yTrain, yTest, nil
+}
+
+// allocate splits total between classes in proportion to counts, giving
+// every class at least one and leaving it at least one, with the rounding
+// remainder going to the classes furthest below their exact share.
+func allocate(counts []int, total int) []int {
+	n := 0
+	for _, c := range counts {
+		n += c
+	}
+
+	quota := make([]int, len(counts))
+	exact := make([]float64, len(counts))
+	sum := 0
+	for k, c := range counts {
+		exact[k] = float64(total) * float64(c) / float64(n)
+		quota[k] = min(max(int(exact[k]), 1), c-1)
+		sum += quota[k]
+	}
+
+	for sum != total {
+		best := -1
+		for k := range quota {
+			gap := exact[k] - float64(quota[k])
+			if sum < total && quota[k] < counts[k]-1 && (best < 0 || gap > exact[best]-float64(quota[best])) {
+				best = k
+			}
+			if sum > total && quota[k] > 1 && (best < 0 || gap < exact[best]-float64(quota[best])) {
+				best = k
+			}
+		}
+
+		if sum < total {
+			quota[best]++
+			sum++
+		} else {
+			quota[best]--
+			sum--
+		}
+	}
+
+	return quota
+}
+
+// GroupTrainTestSplit holds out testSize of the distinct groups, so all
+// samples sharing a group ID land on the same side; the sample fraction
+// therefore only approximates testSize. At least two groups are needed. A
+// nil rng uses core.DefaultRand.
+func GroupTrainTestSplit(X matrix.Matrix, y []float64, groups []int, testSize float64, rng *rand.Rand) (XTrain, XTest matrix.Matrix, yTrain, yTest []float64, err error) {
+	if err := checkSplit(X, y, testSize); err != nil {
+		return matrix.Matrix{}, matrix.Matrix{}, nil, nil, err
+	}
+	if len(groups) != len(y) {
+		return matrix.Matrix{}, matrix.Matrix{}, nil, nil, fmt.Errorf("%w: %d groups for %d samples", validation.ErrShapeMismatch, len(groups), len(y))
+	}
+
+	byGroup := make(map[int][]int)
+	var ids []int
+	for i, g := range groups {
+		if _, ok := byGroup[g]; !ok {
+			ids = append(ids, g)
+		}
+		byGroup[g] = append(byGroup[g], i)
+	}
+	if len(ids) < 2 {
+		return matrix.Matrix{}, matrix.Matrix{}, nil, nil, errors.New("group split needs at least 2 groups")
+	}
+
+	sort.Ints(ids)
+	shuffleInts(ids, core.RandOrDefault(rng))
+	nTest := min(max(int(float64(len(ids))*testSize), 1), len(ids)-1)
+
+	var train, test []int
+	for k, g := range ids {
+		if k < nTest {
+			test = append(test, byGroup[g]...)
+		} else {
+			train = append(train, byGroup[g]...)
+		}
+	}
+
+	XTrain, yTrain = takeRows(X, y, train)
+	XTest, yTest = takeRows(X, y, test)
+
+	return XTrain, XTest, yTrain, yTest, nil
+}
+
+// TrainValTestSplit randomly partitions the samples into train, validation
+// and test sets holding valSize and testSize of the samples in the last
+// two. Every set must end up non-empty. A nil rng uses core.DefaultRand.
+func TrainValTestSplit(X matrix.Matrix, y []float64, valSize, testSize float64, rng *rand.Rand) (XTrain, XVal, XTest matrix.Matrix, yTrain, yVal, yTest []float64, err error) {
+	fail := func(err error) (matrix.Matrix, matrix.Matrix, matrix.Matrix, []float64, []float64, []float64, error) {
+		return matrix.Matrix{}, matrix.Matrix{}, matrix.Matrix{}, nil, nil, nil, err
+	}
+
+	if err := checkSplit(X, y, testSize); err != nil {
+		return fail(err)
+	}
+	if valSize <= 0 || valSize+testSize >= 1 {
+		return fail(errors.New("valSize must be positive and valSize+testSize below 1"))
+	}
+
+	n := len(y)
+	nTest := int(float64(n) * testSize)
+	nVal := int(float64(n) * valSize)
+	if nTest == 0 || nVal == 0 || n-nTest-nVal == 0 {
+		return fail(fmt.Errorf("%d samples are too few for non-empty train, validation and test sets", n))
+	}
+
+	perm := core.RandOrDefault(rng).Perm(n)
+
+	XTest, yTest = takeRows(X, y, perm[:nTest])
+	XVal, yVal = takeRows(X, y, perm[nTest:nTest+nVal])
+	XTrain, yTrain = takeRows(X, y, perm[nTest+nVal:])
+
+	return XTrain, XVal, XTest, yTrain, yVal, yTest, nil
+}
+
+func checkSplit(X matrix.Matrix, y []float64, testSize float64) error {
+	if X.Rows != len(y) {
+		return fmt.Errorf("%w: X has %d samples but y has %d", validation.ErrShapeMismatch, X.Rows, len(y))
+	}
+	if testSize <= 0.0 || testSize >= 1.0 {
+		return errors.New("testSize must be between 0 and 1")
+	}
+
+	return nil
+}
+
+// takeRows copies the samples in idx, in that order.
+func takeRows(X matrix.Matrix, y []float64, idx []int) (matrix.Matrix, []float64) {
+	ySub := make([]float64, len(idx))
+	for k, i := range idx {
+		ySub[k] = y[i]
+	}
+
+	return X.SelectRows(idx), ySub
+}
+
+func shuffleInts(s []int, rng *rand.Rand) {
+	rng.Shuffle(len(s), func(a, b int) { s[a], s[b] = s[b], s[a] })
+}
@@ -0,0 +1,203 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+
+	"golearn-lite/core"
+	"golearn-lite/matrix"
+)
+
+// indexed returns n samples whose only feature is the row index, so a
+// split can be traced back to the rows it took, and labels y[i] = class(i).
+func indexed(n int, class func(i int) float64) (matrix.Matrix, []float64) {
+	X := matrix.Zeros(n, 1)
+	y := make([]float64, n)
+	for i := range y {
+		X.Set(i, 0, float64(i))
+		y[i] = class(i)
+	}
+
+	return X, y
+}
+
+// checkPartition fails unless the parts hold every row index below n
+// exactly once.
+func checkPartition(t *testing.T, n int, parts ...matrix.Matrix) {
+	t.Helper()
+
+	seen := make([]bool, n)
+	for _, X := range parts {
+		for i := 0; i < X.Rows; i++ {
+			row := int(X.At(i, 0))
+			if seen[row] {
+				t.Fatalf("row %d appears twice", row)
+			}
+			seen[row] = true
+		}
+	}
+	for row, ok := range seen {
+		if !ok {
+			t.Fatalf("row %d is in no split", row)
+		}
+	}
+}
+
+func checkLabels(t *testing.T, X matrix.Matrix, ySub, y []float64) {
+	t.Helper()
+
+	for i := 0; i < X.Rows; i++ {
+		if ySub[i] != y[int(X.At(i, 0))] {
+			t.Fatalf("sample %d carries label %v, want %v", i, ySub[i], y[int(X.At(i, 0))])
+		}
+	}
+}
+
+func TestStratifiedTrainTestSplit(t *testing.T) {
+	// 60 of class 0, 30 of class 1, 10 of class 2
+	X, y := indexed(100, func(i int) float64 {
+		switch {
+		case i < 60:
+			return 0
+		case i < 90:
+			return 1
+		}
+		return 2
+	})
+
+	XTrain, XTest, yTrain, yTest, err := StratifiedTrainTestSplit(X, y, 0.2, core.NewRand(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPartition(t, 100, XTrain, XTest)
+	checkLabels(t, XTrain, yTrain, y)
+	checkLabels(t, XTest, yTest, y)
+
+	counts := map[float64]int{}
+	for _, label := range yTest {
+		counts[label]++
+	}
+	if want := map[float64]int{0: 12, 1: 6, 2: 2}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("test class counts %v, want %v", counts, want)
+	}
+
+	_, XTest2, _, _, _ := StratifiedTrainTestSplit(X, y, 0.2, core.NewRand(1))
+	if !reflect.DeepEqual(XTest.Data, XTest2.Data) {
+		t.Error("the same seed gave different splits")
+	}
+}
+
+func TestStratifiedTrainTestSplitErrors(t *testing.T) {
+	X, y := indexed(10, func(i int) float64 { return float64(i % 2) })
+
+	if _, _, _, _, err := StratifiedTrainTestSplit(X, y, 0.1, core.NewRand(1)); err == nil {
+		t.Error("expected an error when the test set cannot hold every class")
+	}
+
+	y[9] = 2
+	if _, _, _, _, err := StratifiedTrainTestSplit(X, y, 0.3, core.NewRand(1)); err == nil {
+		t.Error("expected an error for a class with a single sample")
+	}
+	if _, _, _, _, err := StratifiedTrainTestSplit(X, y[:5], 0.3, core.NewRand(1)); err == nil {
+		t.Error("expected an error for mismatched X and y")
+	}
+}
+
+func TestGroupTrainTestSplit(t *testing.T) {
+	X, y := indexed(40, func(i int) float64 { return float64(i % 2) })
+	groups := make([]int, 40)
+	for i := range groups {
+		groups[i] = i / 4
+	}
+
+	XTrain, XTest, yTrain, yTest, err := GroupTrainTestSplit(X, y, groups, 0.3, core.NewRand(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPartition(t, 40, XTrain, XTest)
+	checkLabels(t, XTrain, yTrain, y)
+	checkLabels(t, XTest, yTest, y)
+
+	if XTest.Rows != 12 {
+		t.Errorf("%d test samples, want 3 groups of 4", XTest.Rows)
+	}
+	side := map[int]bool{}
+	for i := 0; i < XTest.Rows; i++ {
+		side[groups[int(XTest.At(i, 0))]] = true
+	}
+	for i := 0; i < XTrain.Rows; i++ {
+		if g := groups[int(XTrain.At(i, 0))]; side[g] {
+			t.Fatalf("group %d is on both sides", g)
+		}
+	}
+
+	if _, _, _, _, err := GroupTrainTestSplit(X, y, make([]int, 40), 0.3, nil); err == nil {
+		t.Error("expected an error for a single group")
+	}
+	if _, _, _, _, err := GroupTrainTestSplit(X, y, groups[:10], 0.3, nil); err == nil {
+		t.Error("expected an error for too few group IDs")
+	}
+}
+
+func TestTrainValTestSplit(t *testing.T) {
+	X, y := indexed(50, func(i int) float64 { return float64(i) })
+
+	XTrain, XVal, XTest, yTrain, yVal, yTest, err := TrainValTestSplit(X, y, 0.2, 0.1, core.NewRand(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if XTrain.Rows != 35 || XVal.Rows != 10 || XTest.Rows != 5 {
+		t.Errorf("sizes %d/%d/%d, want 35/10/5", XTrain.Rows, XVal.Rows, XTest.Rows)
+	}
+	checkPartition(t, 50, XTrain, XVal, XTest)
+	checkLabels(t, XTrain, yTrain, y)
+	checkLabels(t, XVal, yVal, y)
+	checkLabels(t, XTest, yTest, y)
+
+	for _, sizes := range [][2]float64{{0, 0.2}, {0.5, 0.5}, {0.01, 0.2}} {
+		if _, _, _, _, _, _, err := TrainValTestSplit(X, y, sizes[0], sizes[1], nil); err == nil {
+			t.Errorf("sizes %v: expected an error", sizes)
+		}
+	}
+}
+
+func TestSeededSplitsAreReproducible(t *testing.T) {
+	X := make([][]float64, 20)
+	y := make([]float64, 20)
+	for i := range X {
+		X[i], y[i] = []float64{float64(i)}, float64(i)
+	}
+
+	XTrain, XTest, yTrain, yTest, err := TrainTestSplitRand(X, y, 0.25, core.NewRand(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(XTrain) != 15 || len(XTest) != 5 || len(yTrain) != 15 || len(yTest) != 5 {
+		t.Fatalf("sizes %d/%d, want 15/5", len(XTrain), len(XTest))
+	}
+	_, XTest2, _, _, _ := TrainTestSplitRand(X, y, 0.25, core.NewRand(4))
+	if !reflect.DeepEqual(XTest, XTest2) {
+		t.Error("the same seed gave different splits")
+	}
+
+	XS, yS, err := ShuffleRand(X, y, core.NewRand(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	XS2, _, _ := ShuffleRand(X, y, core.NewRand(5))
+	if !reflect.DeepEqual(XS, XS2) {
+		t.Error("the same seed gave different shuffles")
+	}
+	seen := make([]bool, 20)
+	for i, row := range XS {
+		if row[0] != yS[i] {
+			t.Fatalf("sample %d lost its label", i)
+		}
+		seen[int(row[0])] = true
+	}
+	for i, ok := range seen {
+		if !ok {
+			t.Fatalf("row %d missing after shuffle", i)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrNotPositiveDefinite = errors.New("matrix is not symmetric positive definite")
+
+// CholeskyFactors holds A = L·Lᵀ for a symmetric positive definite A.
+type CholeskyFactors struct {
+	l Matrix
+}
+
+func Cholesky(a Matrix) (*CholeskyFactors, error) {
+	if a.Rows != a.Cols {
+		return nil, ErrNotSquare
+	}
+
+	n := a.Rows
+	l := Zeros(n, n)
+
+	for j := 0; j < n; j++ {
+		lj := l.Row(j)
+		d := 0.0
+		for k := 0; k < j; k++ {
+			lk := l.Row(k)
+			s := 0.0
+			for i := 0; i < k; i++ {
+				s += lk[i] * lj[i]
+			}
+			s = (a.At(j, k) - s) / lk[k]
+			lj[k] = s
+			d += s * s
+
+			if math.Abs(a.At(k, j)-a.At(j, k)) > epsilon*64*math.Max(math.Abs(a.At(k, j)), 1) {
+				return nil, ErrNotPositiveDefinite
+			}
+		}
+
+		d = a.At(j, j) - d
+		if d <= 0 {
+			return nil, ErrNotPositiveDefinite
+		}
+		lj[j] = math.Sqrt(d)
+	}
+
+	return &CholeskyFactors{l: l}, nil
+}
+
+func (f *CholeskyFactors) L() Matrix {
+	return f.l.Copy()
+}
+
+func (f *CholeskyFactors) Det() float64 {
+	det := 1.0
+	for i := 0; i < f.l.Rows; i++ {
+		det *= f.l.At(i, i)
+	}
+
+	return det * det
+}
+
+// Solve returns X with A·X = B.
+func (f *CholeskyFactors) Solve(b Matrix) (Matrix, error) {
+	n := f.l.Rows
+	if b.Rows != n {
+		return Matrix{}, ErrDimensionMismatch
+	}
+
+	x := b.Copy()
+
+	// Solve L·Y = B
+	for k := 0; k < n; k++ {
+		xk := x.Row(k)
+		for i := 0; i < k; i++ {
+			lki := f.l.At(k, i)
+			xi := x.Row(i)
+			for j := range xk {
+				xk[j] -= xi[j] * lki
+			}
+		}
+		lkk := f.l.At(k, k)
+		for j := range xk {
+			xk[j] /= lkk
+		}
+	}
+
+	// Solve Lᵀ·X = Y
+	for k := n - 1; k >= 0; k-- {
+		xk := x.Row(k)
+		for i := k + 1; i < n; i++ {
+			lik := f.l.At(i, k)
+			xi := x.Row(i)
+			for j := range xk {
+				xk[j] -= xi[j] * lik
+			}
+		}
+		lkk := f.l.At(k, k)
+		for j := range xk {
+			xk[j] /= lkk
+		}
+	}
+
+	return x, nil
+}
@@ -0,0 +1,130 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func mustDot(t *testing.T, a, b Matrix) Matrix {
+	t.Helper()
+
+	out, err := a.Dot(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return out
+}
+
+func TestLU(t *testing.T) {
+	rng := rand.New(rand.NewSource(10))
+	a := randomMatrix(rng, 6, 6)
+
+	f, err := LU(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// P·A = L·U
+	pa := a.SelectRows(f.Pivot())
+	assertClose(t, mustDot(t, f.L(), f.U()), pa)
+
+	b := randomMatrix(rng, 6, 2)
+	x, err := f.Solve(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, mustDot(t, a, x), b)
+
+	inv, err := a.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, mustDot(t, a, inv), Identity(6))
+}
+
+func TestLUDetAndSingular(t *testing.T) {
+	a := New([][]float64{{0, 2, 1}, {1, 1, 0}, {3, 0, 1}})
+	det, err := Det(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Expanding along the first row: 0·1 - 2·(1-0) + 1·(0-3) = -5
+	if math.Abs(det+5) > 1e-12 {
+		t.Errorf("det = %v, want -5", det)
+	}
+
+	singular := New([][]float64{{1, 2}, {2, 4}})
+	if _, err := Solve(singular, New([][]float64{{1}, {2}})); !errors.Is(err, ErrSingular) {
+		t.Errorf("singular solve: err = %v, want ErrSingular", err)
+	}
+	if c, _ := Cond(singular); !math.IsInf(c, 1) {
+		t.Errorf("singular cond = %v, want +Inf", c)
+	}
+	if _, err := LU(Zeros(2, 3)); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("non-square LU: err = %v", err)
+	}
+}
+
+func TestQR(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	a := randomMatrix(rng, 8, 4)
+
+	f, err := QR(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, r := f.Q(), f.R()
+
+	assertClose(t, mustDot(t, q, r), a)
+	assertClose(t, mustDot(t, q.Transpose(), q), Identity(4))
+	for i := 0; i < r.Rows; i++ {
+		for j := 0; j < i; j++ {
+			if r.At(i, j) != 0 {
+				t.Fatalf("R(%d, %d) = %v, want 0", i, j, r.At(i, j))
+			}
+		}
+	}
+
+	// Least squares: the residual is orthogonal to the columns of A
+	b := randomMatrix(rng, 8, 1)
+	x, err := Solve(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ax := mustDot(t, a, x)
+	residual, _ := b.Sub(ax)
+	assertClose(t, mustDot(t, a.Transpose(), residual), Zeros(4, 1))
+}
+
+func TestCholesky(t *testing.T) {
+	rng := rand.New(rand.NewSource(12))
+	m := randomMatrix(rng, 5, 5)
+
+	// MᵀM + I is symmetric positive definite
+	a, _ := mustDot(t, m.Transpose(), m).Add(Identity(5))
+	f, err := Cholesky(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := f.L()
+	assertClose(t, mustDot(t, l, l.Transpose()), a)
+
+	b := randomMatrix(rng, 5, 3)
+	x, err := f.Solve(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, mustDot(t, a, x), b)
+
+	lu, _ := LU(a)
+	if math.Abs(f.Det()-lu.Det()) > 1e-9*math.Abs(lu.Det()) {
+		t.Errorf("Cholesky det %v, LU det %v", f.Det(), lu.Det())
+	}
+
+	if _, err := Cholesky(New([][]float64{{1, 2}, {2, 1}})); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Errorf("indefinite input: err = %v, want ErrNotPositiveDefinite", err)
+	}
+}
@@ -0,0 +1,252 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+var ErrNotSymmetric = errors.New("matrix must be symmetric")
+
+// EigenFactors holds the eigenpairs of a symmetric matrix sorted by
+// decreasing eigenvalue. Column k of Vectors belongs to Values[k].
+type EigenFactors struct {
+	Values  []float64
+	Vectors Matrix
+}
+
+// EigenSym computes every eigenpair of a symmetric matrix with cyclic
+// Jacobi rotations.
+func EigenSym(a Matrix) (*EigenFactors, error) {
+	if a.Rows != a.Cols {
+		return nil, ErrNotSquare
+	}
+	if !isSymmetric(a) {
+		return nil, ErrNotSymmetric
+	}
+
+	n := a.Rows
+	w := a.Copy()
+	v := Identity(n)
+
+	scale := 0.0
+	for i := 0; i < n; i++ {
+		for _, x := range w.Row(i) {
+			scale += x * x
+		}
+	}
+
+	converged := n < 2
+	for sweep := 0; sweep < maxJacobiSweeps && !converged; sweep++ {
+		off := 0.0
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				off += w.At(p, q) * w.At(p, q)
+			}
+		}
+		if off <= epsilon*epsilon*scale {
+			converged = true
+			break
+		}
+
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				apq := w.At(p, q)
+				if apq == 0 {
+					continue
+				}
+
+				theta := (w.At(q, q) - w.At(p, p)) / (2 * apq)
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				// A ← Pᵀ·A·P and V ← V·P
+				for k := 0; k < n; k++ {
+					akp, akq := w.At(k, p), w.At(k, q)
+					w.Set(k, p, c*akp-s*akq)
+					w.Set(k, q, s*akp+c*akq)
+				}
+				rotate(w.Row(p), w.Row(q), c, s)
+				for k := 0; k < n; k++ {
+					vkp, vkq := v.At(k, p), v.At(k, q)
+					v.Set(k, p, c*vkp-s*vkq)
+					v.Set(k, q, s*vkp+c*vkq)
+				}
+			}
+		}
+	}
+	if !converged {
+		return nil, ErrNoConvergence
+	}
+
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = w.At(i, i)
+	}
+
+	return sortedEigen(values, v, n), nil
+}
+
+// sortedEigen orders the first k eigenpairs by decreasing value.
+func sortedEigen(values []float64, vectors Matrix, k int) *EigenFactors {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return values[order[i]] > values[order[j]] })
+
+	out := &EigenFactors{Values: make([]float64, k), Vectors: Zeros(vectors.Rows, k)}
+	for c, j := range order[:k] {
+		out.Values[c] = values[j]
+		for i := 0; i < vectors.Rows; i++ {
+			out.Vectors.Set(i, c, vectors.At(i, j))
+		}
+	}
+
+	return out
+}
+
+// EigenSymTopK approximates the k eigenpairs of largest magnitude with
+// subspace iteration and a Rayleigh-Ritz projection, touching a only
+// through products. It suits large matrices where k is small. maxIter <= 0
+// and tol <= 0 select 300 iterations and 1e-10. If the Ritz values have
+// not settled after maxIter iterations, the last approximation is returned
+// together with ErrNoConvergence.
+func EigenSymTopK(a Matrix, k, maxIter int, tol float64) (*EigenFactors, error) {
+	if a.Rows != a.Cols {
+		return nil, ErrNotSquare
+	}
+	if !isSymmetric(a) {
+		return nil, ErrNotSymmetric
+	}
+
+	n := a.Rows
+	if k <= 0 || k > n {
+		return nil, errors.New("k must be between 1 and the matrix size")
+	}
+	if maxIter <= 0 {
+		maxIter = 300
+	}
+	if tol <= 0 {
+		tol = 1e-10
+	}
+
+	// A few extra vectors speed up convergence of the wanted ones
+	p := min(n, k+min(k, 8))
+
+	// Fixed seed so repeated calls agree
+	rng := rand.New(rand.NewSource(1))
+	q := Zeros(n, p)
+	for i := range q.Data {
+		q.Data[i] = rng.NormFloat64()
+	}
+
+	var prev []float64
+	for iter := 0; iter < maxIter; iter++ {
+		z, err := a.Dot(q)
+		if err != nil {
+			return nil, err
+		}
+		f, err := QR(z)
+		if err != nil {
+			return nil, err
+		}
+		q = f.Q()
+
+		aq, _ := a.Dot(q)
+		h, _ := q.Transpose().Dot(aq)
+		symmetrize(h)
+		ritz, err := EigenSym(h)
+		if err != nil {
+			return nil, err
+		}
+
+		// Converge on the pairs that are returned: the k largest in
+		// magnitude, not the k largest in value
+		top := largestMagnitudes(ritz.Values, k)
+		done := prev != nil
+		for i := 0; i < k && done; i++ {
+			if math.Abs(top[i]-prev[i]) > tol*math.Max(1, math.Abs(top[i])) {
+				done = false
+			}
+		}
+		prev = top
+
+		if done || iter == maxIter-1 {
+			vectors, _ := q.Dot(ritz.Vectors)
+			out := sortedByMagnitude(ritz.Values, vectors, k)
+			if !done {
+				return out, ErrNoConvergence
+			}
+
+			return out, nil
+		}
+	}
+
+	return nil, ErrNoConvergence
+}
+
+// largestMagnitudes returns the k values of largest magnitude, in that
+// order.
+func largestMagnitudes(values []float64, k int) []float64 {
+	top := append([]float64(nil), values...)
+	sort.SliceStable(top, func(i, j int) bool { return math.Abs(top[i]) > math.Abs(top[j]) })
+
+	return top[:k]
+}
+
+// sortedByMagnitude keeps the k pairs with the largest |value| and returns
+// them by decreasing value.
+func sortedByMagnitude(values []float64, vectors Matrix, k int) *EigenFactors {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return math.Abs(values[order[i]]) > math.Abs(values[order[j]])
+	})
+
+	keptValues := make([]float64, k)
+	kept := Zeros(vectors.Rows, k)
+	for c, j := range order[:k] {
+		keptValues[c] = values[j]
+		for i := 0; i < vectors.Rows; i++ {
+			kept.Set(i, c, vectors.At(i, j))
+		}
+	}
+
+	return sortedEigen(keptValues, kept, k)
+}
+
+func isSymmetric(a Matrix) bool {
+	maxAbs := 0.0
+	for i := 0; i < a.Rows; i++ {
+		for _, x := range a.Row(i) {
+			maxAbs = math.Max(maxAbs, math.Abs(x))
+		}
+	}
+
+	tol := 1e-10 * math.Max(1, maxAbs)
+	for i := 0; i < a.Rows; i++ {
+		for j := i + 1; j < a.Cols; j++ {
+			if math.Abs(a.At(i, j)-a.At(j, i)) > tol {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// symmetrize averages away the rounding asymmetry of a computed Qᵀ·A·Q.
+func symmetrize(a Matrix) {
+	for i := 0; i < a.Rows; i++ {
+		for j := i + 1; j < a.Cols; j++ {
+			avg := (a.At(i, j) + a.At(j, i)) / 2
+			a.Set(i, j, avg)
+			a.Set(j, i, avg)
+		}
+	}
+}
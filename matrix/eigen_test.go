@@ -0,0 +1,109 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// symWithEigenvalues returns Q·diag(values)·Qᵀ for a random orthogonal Q.
+func symWithEigenvalues(rng *rand.Rand, values []float64) Matrix {
+	n := len(values)
+	f, err := QR(randomMatrix(rng, n, n))
+	if err != nil {
+		panic(err)
+	}
+	q := f.Q()
+
+	d := Zeros(n, n)
+	for i, v := range values {
+		d.Set(i, i, v)
+	}
+	qd, _ := q.Dot(d)
+	a, _ := qd.Dot(q.Transpose())
+	symmetrize(a)
+
+	return a
+}
+
+// checkEigenpairs verifies A·v = λ·v and unit length for every pair.
+func checkEigenpairs(t *testing.T, a Matrix, f *EigenFactors, tol float64) {
+	t.Helper()
+
+	for c, lambda := range f.Values {
+		v := f.Vectors.Col(c)
+		vm, _ := NewFromSlice(len(v), 1, v)
+		av, _ := a.Dot(vm)
+
+		norm := 0.0
+		for i := range v {
+			norm += v[i] * v[i]
+			if diff := math.Abs(av.At(i, 0) - lambda*v[i]); diff > tol {
+				t.Fatalf("pair %d (λ = %v): residual %v at row %d", c, lambda, diff, i)
+			}
+		}
+		if math.Abs(norm-1) > tol {
+			t.Fatalf("pair %d: |v|² = %v", c, norm)
+		}
+	}
+}
+
+func TestEigenSym(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	want := []float64{7, 3, 1, -2, -5}
+	a := symWithEigenvalues(rng, want)
+
+	f, err := EigenSym(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range want {
+		if math.Abs(f.Values[i]-v) > 1e-9 {
+			t.Fatalf("values %v, want %v", f.Values, want)
+		}
+	}
+	checkEigenpairs(t, a, f, 1e-9)
+
+	if _, err := EigenSym(New([][]float64{{1, 2}, {3, 4}})); !errors.Is(err, ErrNotSymmetric) {
+		t.Errorf("asymmetric input: err = %v", err)
+	}
+}
+
+func TestEigenSymTopKLargestMagnitude(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+
+	// The largest magnitudes are negative, so the k largest values are
+	// not the ones returned
+	values := []float64{-40, -30, 20, 3, 2, 1.5, 1, 0.5, 0.25, 0.1, 0, -0.1}
+	a := symWithEigenvalues(rng, values)
+
+	f, err := EigenSymTopK(a, 3, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := append([]float64(nil), f.Values...)
+	sort.Float64s(got)
+	want := []float64{-40, -30, 20}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-8 {
+			t.Fatalf("values %v, want %v", f.Values, want)
+		}
+	}
+	checkEigenpairs(t, a, f, 1e-6)
+}
+
+func TestEigenSymTopKNoConvergence(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	a := symWithEigenvalues(rng, []float64{5, 4.999, 1, 0.5, 0.2, 0.1})
+
+	f, err := EigenSymTopK(a, 1, 1, 1e-15)
+	if !errors.Is(err, ErrNoConvergence) {
+		t.Fatalf("err = %v, want ErrNoConvergence", err)
+	}
+	if f == nil || len(f.Values) != 1 {
+		t.Fatal("expected the last approximation with ErrNoConvergence")
+	}
+}
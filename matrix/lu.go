@@ -0,0 +1,207 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrNotSquare = errors.New("matrix must be square")
+	ErrSingular  = errors.New("matrix is singular")
+)
+
+// Machine epsilon for float64, used to scale rank and singularity tolerances.
+const epsilon = 0x1p-52
+
+// LUFactors holds the factorization P·A = L·U computed with partial
+// pivoting. L is unit lower triangular and shares storage with U.
+type LUFactors struct {
+	lu    Matrix
+	pivot []int
+	sign  float64
+}
+
+func LU(a Matrix) (*LUFactors, error) {
+	if a.Rows != a.Cols {
+		return nil, ErrNotSquare
+	}
+
+	n := a.Rows
+	lu := a.Copy()
+	pivot := make([]int, n)
+	for i := range pivot {
+		pivot[i] = i
+	}
+	sign := 1.0
+
+	for k := 0; k < n; k++ {
+		// Pick the largest remaining entry in column k as the pivot
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(lu.At(i, k)) > math.Abs(lu.At(p, k)) {
+				p = i
+			}
+		}
+
+		if p != k {
+			rp, rk := lu.Row(p), lu.Row(k)
+			for j := range rk {
+				rp[j], rk[j] = rk[j], rp[j]
+			}
+			pivot[p], pivot[k] = pivot[k], pivot[p]
+			sign = -sign
+		}
+
+		ukk := lu.At(k, k)
+		if ukk == 0 {
+			continue
+		}
+
+		rk := lu.Row(k)
+		for i := k + 1; i < n; i++ {
+			row := lu.Row(i)
+			row[k] /= ukk
+			lik := row[k]
+			for j := k + 1; j < n; j++ {
+				row[j] -= lik * rk[j]
+			}
+		}
+	}
+
+	return &LUFactors{lu: lu, pivot: pivot, sign: sign}, nil
+}
+
+// IsSingular reports whether U has a pivot that is zero relative to the
+// largest pivot.
+func (f *LUFactors) IsSingular() bool {
+	n := f.lu.Rows
+	maxPivot := 0.0
+	for i := 0; i < n; i++ {
+		maxPivot = math.Max(maxPivot, math.Abs(f.lu.At(i, i)))
+	}
+
+	tol := float64(n) * maxPivot * epsilon
+	for i := 0; i < n; i++ {
+		if math.Abs(f.lu.At(i, i)) <= tol {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *LUFactors) L() Matrix {
+	n := f.lu.Rows
+	l := Identity(n)
+
+	for i := 0; i < n; i++ {
+		copy(l.Row(i)[:i], f.lu.Row(i)[:i])
+	}
+
+	return l
+}
+
+func (f *LUFactors) U() Matrix {
+	n := f.lu.Rows
+	u := Zeros(n, n)
+
+	for i := 0; i < n; i++ {
+		copy(u.Row(i)[i:], f.lu.Row(i)[i:])
+	}
+
+	return u
+}
+
+// Pivot returns the row permutation: row i of P·A is row Pivot()[i] of A.
+func (f *LUFactors) Pivot() []int {
+	return append([]int(nil), f.pivot...)
+}
+
+func (f *LUFactors) Det() float64 {
+	det := f.sign
+	for i := 0; i < f.lu.Rows; i++ {
+		det *= f.lu.At(i, i)
+	}
+
+	return det
+}
+
+// Solve returns X with A·X = B.
+func (f *LUFactors) Solve(b Matrix) (Matrix, error) {
+	n := f.lu.Rows
+	if b.Rows != n {
+		return Matrix{}, ErrDimensionMismatch
+	}
+	if f.IsSingular() {
+		return Matrix{}, ErrSingular
+	}
+
+	x := Zeros(n, b.Cols)
+	for i, p := range f.pivot {
+		copy(x.Row(i), b.Row(p))
+	}
+
+	// Forward substitution with unit L
+	for k := 0; k < n; k++ {
+		xk := x.Row(k)
+		for i := k + 1; i < n; i++ {
+			lik := f.lu.At(i, k)
+			if lik == 0 {
+				continue
+			}
+			xi := x.Row(i)
+			for j := range xi {
+				xi[j] -= lik * xk[j]
+			}
+		}
+	}
+
+	// Back substitution with U
+	for k := n - 1; k >= 0; k-- {
+		xk := x.Row(k)
+		ukk := f.lu.At(k, k)
+		for j := range xk {
+			xk[j] /= ukk
+		}
+		for i := 0; i < k; i++ {
+			uik := f.lu.At(i, k)
+			if uik == 0 {
+				continue
+			}
+			xi := x.Row(i)
+			for j := range xi {
+				xi[j] -= uik * xk[j]
+			}
+		}
+	}
+
+	return x, nil
+}
+
+// solveTransposeVec solves Aᵀ·x = b for a single right-hand side.
+func (f *LUFactors) solveTransposeVec(b []float64) []float64 {
+	n := f.lu.Rows
+	w := append([]float64(nil), b...)
+
+	// Uᵀ is lower triangular
+	for i := 0; i < n; i++ {
+		for k := 0; k < i; k++ {
+			w[i] -= f.lu.At(k, i) * w[k]
+		}
+		w[i] /= f.lu.At(i, i)
+	}
+
+	// Lᵀ is unit upper triangular
+	for i := n - 1; i >= 0; i-- {
+		for k := i + 1; k < n; k++ {
+			w[i] -= f.lu.At(k, i) * w[k]
+		}
+	}
+
+	x := make([]float64, n)
+	for i, p := range f.pivot {
+		x[p] = w[i]
+	}
+
+	return x
+}
@@ -2,93 +2,210 @@ package matrix
 
 import (
 	"errors"
+	"runtime"
+	"sync"
 )
 
 
+// Matrix is a dense row-major matrix. Element (i, j) lives at
+// Data[i*Stride+j]; Stride is at least Cols.
 type Matrix struct {
-	Data [][]float64
-	Rows int
-	Cols int
+	Data   []float64
+	Rows   int
+	Cols   int
+	Stride int
 }
 
+// Block edge used by Dot and Transpose, and the amount of work (in
+// multiply-adds) below which Dot stays on the calling goroutine.
+const (
+	blockSize      = 64
+	parallelCutoff = 1 << 16
+)
+
 func New(data [][]float64) Matrix {
-	return Matrix{Data: data, Rows: len(data), Cols: len(data[0])}
-}
+	if len(data) == 0 {
+		return Matrix{}
+	}
+
+	rows, cols := len(data), len(data[0])
+	m := Zeros(rows, cols)
 
-func (m Matrix) Transpose() Matrix {
-	data := make([][]float64, m.Cols)
 	for i := range data {
-		data[i] = make([]float64, m.Rows)
-		for j := range data[i] {
-			data[i][j] = m.Data[j][i]
-		}
+		copy(m.Row(i), data[i])
 	}
 
-	return New(data)
+	return m
 }
 
-func (m Matrix) Dot(n Matrix) (Matrix, error) {
-	if m.Cols != n.Rows {
-		return Matrix{}, errors.New("matrix dimensions do not match for dot product")
+func Zeros(rows, cols int) Matrix {
+	return Matrix{Data: make([]float64, rows*cols), Rows: rows, Cols: cols, Stride: cols}
+}
+
+// NewFromSlice wraps a row-major slice of length rows*cols without copying.
+func NewFromSlice(rows, cols int, data []float64) (Matrix, error) {
+	if len(data) != rows*cols {
+		return Matrix{}, errors.New("data length does not match matrix dimensions")
 	}
 
-	data := make([][]float64, n.Cols)
-	for i := range data {
-		for j := 0; j < n.Cols; j++ {
-			sum := 0.0
-			for k := 0; k < m.Cols; k++ {
-				sum += m.Data[i][k] * n.Data[k][j]
-			}
-			data[i][j] = sum
-		}
+	return Matrix{Data: data, Rows: rows, Cols: cols, Stride: cols}, nil
+}
+
+func Identity(n int) Matrix {
+	m := Zeros(n, n)
+	for i := 0; i < n; i++ {
+		m.Data[i*m.Stride+i] = 1
 	}
 
-	return New(data), nil
+	return m
 }
 
-func (m Matrix) Inverse() (Matrix, error) {
-	if m.Rows != m.Cols {
-		return Matrix{}, errors.New("only square matrices can be inverted")
+func (m Matrix) At(i, j int) float64 {
+	return m.Data[i*m.Stride+j]
+}
+
+func (m Matrix) Set(i, j int, v float64) {
+	m.Data[i*m.Stride+j] = v
+}
+
+// Row returns row i as a slice sharing storage with m.
+func (m Matrix) Row(i int) []float64 {
+	start := i * m.Stride
+	return m.Data[start : start+m.Cols : start+m.Cols]
+}
+
+// Copy returns a deep copy of m with its own compact storage.
+func (m Matrix) Copy() Matrix {
+	out := Zeros(m.Rows, m.Cols)
+	for i := 0; i < m.Rows; i++ {
+		copy(out.Row(i), m.Row(i))
 	}
 
-	n := m.Rows
-	aug := make([][]float64, n)
+	return out
+}
 
-	for i := 0; i < n; i++ {
-		aug[i] = make([]float64, 2*n)
-		for j := 0; j < n; j++ {
-			aug[i][j] = m.Data[i][j]
+// ToSlices copies m into a freshly allocated [][]float64.
+func (m Matrix) ToSlices() [][]float64 {
+	out := make([][]float64, m.Rows)
+	for i := range out {
+		out[i] = make([]float64, m.Cols)
+		copy(out[i], m.Row(i))
+	}
+
+	return out
+}
+
+func (m Matrix) Transpose() Matrix {
+	t := Zeros(m.Cols, m.Rows)
+	transpose(dense[float64]{m.Data, m.Rows, m.Cols, m.Stride}, dense[float64]{t.Data, t.Rows, t.Cols, t.Stride})
+
+	return t
+}
+
+func transpose[T Float](m, t dense[T]) {
+	for ii := 0; ii < m.rows; ii += blockSize {
+		iMax := min(ii+blockSize, m.rows)
+		for jj := 0; jj < m.cols; jj += blockSize {
+			jMax := min(jj+blockSize, m.cols)
+			for i := ii; i < iMax; i++ {
+				row := m.data[i*m.stride:]
+				for j := jj; j < jMax; j++ {
+					t.data[j*t.stride+i] = row[j]
+				}
+			}
 		}
+	}
+}
 
-		aug[i][n+i] = 1
+func (m Matrix) Dot(n Matrix) (Matrix, error) {
+	if m.Cols != n.Rows {
+		return Matrix{}, errors.New("matrix dimensions do not match for dot product")
 	}
 
-	// Gaussian elimination
-	for i := 0; i < n; i++ {
-		pivot := aug[i][i]
-		if pivot == 0 {
-			return Matrix{}, errors.New("matrix is singular and cannot be inverted")
-		}
+	out := Zeros(m.Rows, n.Cols)
+	parallelDot(
+		dense[float64]{m.Data, m.Rows, m.Cols, m.Stride},
+		dense[float64]{n.Data, n.Rows, n.Cols, n.Stride},
+		dense[float64]{out.Data, out.Rows, out.Cols, out.Stride},
+	)
 
-		for j := 0; j < 2*n; j++ {
-			aug[i][j] /= pivot
-		}
-		for k := 0; k < n; k++ {
-			if k != i {
-				factor := aug[k][i]
-				for j := 0; j < 2*n; j++ {
-					aug[k][j] -= factor * aug[i][j]
+	return out, nil
+}
+
+// dense is the storage shared by Matrix and Matrix32, so the multiply
+// kernels are written once for both element types.
+type dense[T Float] struct {
+	data   []T
+	rows   int
+	cols   int
+	stride int
+}
+
+// parallelDot computes out = a·b, fanning row blocks out to a fixed pool
+// of goroutines once the product is large enough to pay for them.
+func parallelDot[T Float](a, b, out dense[T]) {
+	work := a.rows * a.cols * b.cols
+	workers := runtime.GOMAXPROCS(0)
+
+	if work < parallelCutoff || workers == 1 || a.rows <= blockSize {
+		dotBlock(a, b, out, 0, a.rows)
+		return
+	}
+
+	// Hand out row blocks of the result to a fixed pool of goroutines
+	blocks := make(chan int, (a.rows+blockSize-1)/blockSize)
+	for i := 0; i < a.rows; i += blockSize {
+		blocks <- i
+	}
+	close(blocks)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i0 := range blocks {
+				dotBlock(a, b, out, i0, min(i0+blockSize, a.rows))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// dotBlock accumulates rows [r0, r1) of a*b into out, tiling over the
+// shared dimension and the columns of b so both stay in cache.
+func dotBlock[T Float](a, b, out dense[T], r0, r1 int) {
+	for kk := 0; kk < a.cols; kk += blockSize {
+		kMax := min(kk+blockSize, a.cols)
+		for jj := 0; jj < b.cols; jj += blockSize {
+			jMax := min(jj+blockSize, b.cols)
+			for i := r0; i < r1; i++ {
+				aRow := a.data[i*a.stride:]
+				oRow := out.data[i*out.stride+jj : i*out.stride+jMax]
+				for k := kk; k < kMax; k++ {
+					aik := aRow[k]
+					if aik == 0 {
+						continue
+					}
+					bRow := b.data[k*b.stride+jj : k*b.stride+jMax]
+					for j, bkj := range bRow {
+						oRow[j] += aik * bkj
+					}
 				}
 			}
 		}
 	}
+}
 
-	// Extract inverse
-	inv := make([][]float64, n)
+func (m Matrix) Inverse() (Matrix, error) {
+	if m.Rows != m.Cols {
+		return Matrix{}, errors.New("only square matrices can be inverted")
+	}
 
-	for i := 0; i < n; i++ {
-		inv[i] = aug[i][n:]
+	lu, err := LU(m)
+	if err != nil {
+		return Matrix{}, err
 	}
 
-	return New(inv), nil
+	return lu.Solve(Identity(m.Rows))
 }
@@ -0,0 +1,132 @@
+package matrix
+
+import "errors"
+
+// Float is the set of element types the matrix kernels are written for.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Matrix32 is the float32 counterpart of Matrix for large datasets where
+// halving memory matters more than precision. It satisfies Mat, widening
+// elements to float64 as they are read.
+type Matrix32 struct {
+	Data   []float32
+	Rows   int
+	Cols   int
+	Stride int
+}
+
+func New32(data [][]float32) Matrix32 {
+	if len(data) == 0 {
+		return Matrix32{}
+	}
+
+	rows, cols := len(data), len(data[0])
+	m := Zeros32(rows, cols)
+
+	for i := range data {
+		copy(m.Row(i), data[i])
+	}
+
+	return m
+}
+
+func Zeros32(rows, cols int) Matrix32 {
+	return Matrix32{Data: make([]float32, rows*cols), Rows: rows, Cols: cols, Stride: cols}
+}
+
+// NewFromSlice32 wraps a row-major slice of length rows*cols without copying.
+func NewFromSlice32(rows, cols int, data []float32) (Matrix32, error) {
+	if len(data) != rows*cols {
+		return Matrix32{}, errors.New("data length does not match matrix dimensions")
+	}
+
+	return Matrix32{Data: data, Rows: rows, Cols: cols, Stride: cols}, nil
+}
+
+func (m Matrix32) Dims() (rows, cols int) {
+	return m.Rows, m.Cols
+}
+
+func (m Matrix32) At(i, j int) float64 {
+	return float64(m.Data[i*m.Stride+j])
+}
+
+func (m Matrix32) Set(i, j int, v float32) {
+	m.Data[i*m.Stride+j] = v
+}
+
+// Row returns row i as a slice sharing storage with m.
+func (m Matrix32) Row(i int) []float32 {
+	start := i * m.Stride
+	return m.Data[start : start+m.Cols : start+m.Cols]
+}
+
+func (m Matrix32) DoRowNonZero(i int, fn func(j int, v float64)) {
+	for j, v := range m.Row(i) {
+		if v != 0 {
+			fn(j, float64(v))
+		}
+	}
+}
+
+// Copy returns a deep copy of m with its own compact storage.
+func (m Matrix32) Copy() Matrix32 {
+	out := Zeros32(m.Rows, m.Cols)
+	for i := 0; i < m.Rows; i++ {
+		copy(out.Row(i), m.Row(i))
+	}
+
+	return out
+}
+
+func (m Matrix32) Transpose() Matrix32 {
+	t := Zeros32(m.Cols, m.Rows)
+	transpose(dense[float32]{m.Data, m.Rows, m.Cols, m.Stride}, dense[float32]{t.Data, t.Rows, t.Cols, t.Stride})
+
+	return t
+}
+
+func (m Matrix32) Dot(n Matrix32) (Matrix32, error) {
+	if m.Cols != n.Rows {
+		return Matrix32{}, errors.New("matrix dimensions do not match for dot product")
+	}
+
+	out := Zeros32(m.Rows, n.Cols)
+	parallelDot(
+		dense[float32]{m.Data, m.Rows, m.Cols, m.Stride},
+		dense[float32]{n.Data, n.Rows, n.Cols, n.Stride},
+		dense[float32]{out.Data, out.Rows, out.Cols, out.Stride},
+	)
+
+	return out, nil
+}
+
+// ToFloat64 widens m into a new Matrix.
+func (m Matrix32) ToFloat64() Matrix {
+	out := Zeros(m.Rows, m.Cols)
+	for i := 0; i < m.Rows; i++ {
+		dst := out.Row(i)
+		for j, v := range m.Row(i) {
+			dst[j] = float64(v)
+		}
+	}
+
+	return out
+}
+
+// ToFloat32 narrows m into a new Matrix32, rounding each element.
+func (m Matrix) ToFloat32() Matrix32 {
+	out := Zeros32(m.Rows, m.Cols)
+	for i := 0; i < m.Rows; i++ {
+		dst := out.Row(i)
+		for j, v := range m.Row(i) {
+			dst[j] = float32(v)
+		}
+	}
+
+	return out
+}
+
+var _ Mat = Matrix32{}
@@ -0,0 +1,124 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func randomMatrix(rng *rand.Rand, rows, cols int) Matrix {
+	m := Zeros(rows, cols)
+	for i := range m.Data {
+		m.Data[i] = rng.NormFloat64()
+	}
+
+	return m
+}
+
+// naiveDot is the reference i-j-k triple loop.
+func naiveDot(a, b Matrix) Matrix {
+	out := Zeros(a.Rows, b.Cols)
+	for i := 0; i < a.Rows; i++ {
+		for j := 0; j < b.Cols; j++ {
+			sum := 0.0
+			for k := 0; k < a.Cols; k++ {
+				sum += a.At(i, k) * b.At(k, j)
+			}
+			out.Set(i, j, sum)
+		}
+	}
+
+	return out
+}
+
+func assertClose(t *testing.T, got, want Matrix) {
+	t.Helper()
+
+	if got.Rows != want.Rows || got.Cols != want.Cols {
+		t.Fatalf("shape %dx%d, want %dx%d", got.Rows, got.Cols, want.Rows, want.Cols)
+	}
+	for i := 0; i < want.Rows; i++ {
+		for j := 0; j < want.Cols; j++ {
+			if diff := math.Abs(got.At(i, j) - want.At(i, j)); diff > 1e-9 {
+				t.Fatalf("(%d, %d) = %v, want %v", i, j, got.At(i, j), want.At(i, j))
+			}
+		}
+	}
+}
+
+func TestDot(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	// Sizes on both sides of blockSize and of parallelCutoff
+	sizes := [][3]int{{1, 1, 1}, {3, 5, 2}, {63, 65, 64}, {130, 70, 129}, {300, 90, 40}}
+	for _, sz := range sizes {
+		a, b := randomMatrix(rng, sz[0], sz[1]), randomMatrix(rng, sz[1], sz[2])
+		got, err := a.Dot(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, got, naiveDot(a, b))
+	}
+}
+
+func TestDotStridedViews(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	big := randomMatrix(rng, 200, 150)
+
+	// Views whose Stride exceeds Cols, offset into the parent's storage
+	a := big.Slice(3, 173, 7, 97)
+	b := big.Slice(10, 100, 20, 140)
+	got, err := a.Dot(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, got, naiveDot(a, b))
+
+	// The product of views must match that of compact copies
+	want, _ := a.Copy().Dot(b.Copy())
+	assertClose(t, got, want)
+}
+
+func TestDotDimensionMismatch(t *testing.T) {
+	if _, err := Zeros(2, 3).Dot(Zeros(2, 3)); err == nil {
+		t.Fatal("expected an error for mismatched dimensions")
+	}
+}
+
+// BenchmarkDot compares the reference triple loop, the cache-blocked
+// kernel on one goroutine, and Dot, at the sizes of a typical training
+// run (thousands of samples by hundreds of features).
+func BenchmarkDot(b *testing.B) {
+	rng := rand.New(rand.NewSource(3))
+	sizes := [][3]int{{1000, 100, 100}, {2000, 200, 200}, {4000, 300, 300}}
+
+	for _, sz := range sizes {
+		x, y := randomMatrix(rng, sz[0], sz[1]), randomMatrix(rng, sz[1], sz[2])
+		name := fmt.Sprintf("%dx%dx%d", sz[0], sz[1], sz[2])
+
+		b.Run("naive/"+name, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				naiveDot(x, y)
+			}
+		})
+		b.Run("blocked/"+name, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				out := Zeros(x.Rows, y.Cols)
+				dotBlock(
+					dense[float64]{x.Data, x.Rows, x.Cols, x.Stride},
+					dense[float64]{y.Data, y.Rows, y.Cols, y.Stride},
+					dense[float64]{out.Data, out.Rows, out.Cols, out.Stride},
+					0, x.Rows,
+				)
+			}
+		})
+		b.Run("parallel/"+name, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				if _, err := x.Dot(y); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
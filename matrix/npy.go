@@ -0,0 +1,408 @@
+package matrix
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var npyMagic = []byte("\x93NUMPY")
+
+// npyChunk is the number of elements ReadNPY decodes per read, so memory
+// grows with the data actually present rather than with the header's claim.
+const npyChunk = 1 << 13
+
+// NPYOptions controls how WriteNPYOptions lays out an array. DType is a
+// NumPy type string such as "<f8", "<f4", "<i8" or ">i4"; the zero value
+// writes little-endian float64 in C order.
+type NPYOptions struct {
+	DType        string
+	FortranOrder bool
+}
+
+var (
+	descrPattern   = regexp.MustCompile(`'descr'\s*:\s*'([^']*)'`)
+	fortranPattern = regexp.MustCompile(`'fortran_order'\s*:\s*(True|False)`)
+	shapePattern   = regexp.MustCompile(`'shape'\s*:\s*\(([^)]*)\)`)
+)
+
+// ReadNPY decodes a single .npy array. Supported dtypes are float32/64,
+// signed and unsigned integers of 1 to 8 bytes, and bool, in either byte
+// order and either C or Fortran layout. 0-d arrays become 1×1 and 1-d
+// arrays of length n become n×1 column vectors.
+func ReadNPY(r io.Reader) (Matrix, error) {
+	br := bufio.NewReader(r)
+
+	prefix := make([]byte, 8)
+	if _, err := io.ReadFull(br, prefix); err != nil {
+		return Matrix{}, err
+	}
+	if !bytes.Equal(prefix[:6], npyMagic) {
+		return Matrix{}, errors.New("not a .npy file")
+	}
+
+	var headerLen int
+	switch prefix[6] {
+	case 1:
+		var n uint16
+		if err := binary.Read(br, binary.LittleEndian, &n); err != nil {
+			return Matrix{}, err
+		}
+		headerLen = int(n)
+	case 2, 3:
+		var n uint32
+		if err := binary.Read(br, binary.LittleEndian, &n); err != nil {
+			return Matrix{}, err
+		}
+		headerLen = int(n)
+	default:
+		return Matrix{}, fmt.Errorf("unsupported .npy version %d.%d", prefix[6], prefix[7])
+	}
+
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return Matrix{}, err
+	}
+
+	descr, fortran, shape, err := parseNPYHeader(string(header))
+	if err != nil {
+		return Matrix{}, err
+	}
+	order, kind, size, err := parseDType(descr)
+	if err != nil {
+		return Matrix{}, err
+	}
+
+	rows, cols := 1, 1
+	switch len(shape) {
+	case 0:
+	case 1:
+		rows = shape[0]
+	case 2:
+		rows, cols = shape[0], shape[1]
+	default:
+		return Matrix{}, fmt.Errorf("cannot load a %d-dimensional array into a matrix", len(shape))
+	}
+
+	// Both the raw bytes and the float64 values must be addressable
+	if cols > 0 && rows > math.MaxInt/cols/max(size, 8) {
+		return Matrix{}, fmt.Errorf(".npy shape %v is too large", shape)
+	}
+
+	n := rows * cols
+	values := make([]float64, 0, min(n, npyChunk))
+	raw := make([]byte, min(n, npyChunk)*size)
+	for len(values) < n {
+		chunk := raw[:min(n-len(values), npyChunk)*size]
+		if _, err := io.ReadFull(br, chunk); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return Matrix{}, err
+		}
+		for k := 0; k < len(chunk); k += size {
+			values = append(values, decodeScalar(chunk[k:k+size], order, kind))
+		}
+	}
+
+	if fortran && len(shape) == 2 {
+		// Column-major data is the transpose read row-major
+		t, _ := NewFromSlice(cols, rows, values)
+		return t.Transpose(), nil
+	}
+
+	return NewFromSlice(rows, cols, values)
+}
+
+func parseNPYHeader(header string) (descr string, fortran bool, shape []int, err error) {
+	d := descrPattern.FindStringSubmatch(header)
+	f := fortranPattern.FindStringSubmatch(header)
+	s := shapePattern.FindStringSubmatch(header)
+	if d == nil || f == nil || s == nil {
+		return "", false, nil, errors.New("malformed .npy header")
+	}
+
+	for _, part := range strings.Split(s[1], ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		dim, err := strconv.Atoi(strings.TrimSuffix(part, "L"))
+		if err != nil || dim < 0 {
+			return "", false, nil, fmt.Errorf("malformed .npy shape %q", s[1])
+		}
+		shape = append(shape, dim)
+	}
+
+	return d[1], f[1] == "True", shape, nil
+}
+
+func parseDType(descr string) (binary.ByteOrder, byte, int, error) {
+	if len(descr) < 3 {
+		return nil, 0, 0, fmt.Errorf("unsupported dtype %q", descr)
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	switch descr[0] {
+	case '<', '|', '=':
+	case '>':
+		order = binary.BigEndian
+	default:
+		return nil, 0, 0, fmt.Errorf("unsupported dtype %q", descr)
+	}
+
+	kind := descr[1]
+	size, err := strconv.Atoi(descr[2:])
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("unsupported dtype %q", descr)
+	}
+
+	switch {
+	case kind == 'f' && (size == 4 || size == 8):
+	case (kind == 'i' || kind == 'u') && (size == 1 || size == 2 || size == 4 || size == 8):
+	case kind == 'b' && size == 1:
+	default:
+		return nil, 0, 0, fmt.Errorf("unsupported dtype %q", descr)
+	}
+
+	return order, kind, size, nil
+}
+
+func decodeScalar(b []byte, order binary.ByteOrder, kind byte) float64 {
+	var bits uint64
+	switch len(b) {
+	case 1:
+		bits = uint64(b[0])
+	case 2:
+		bits = uint64(order.Uint16(b))
+	case 4:
+		bits = uint64(order.Uint32(b))
+	case 8:
+		bits = order.Uint64(b)
+	}
+
+	switch kind {
+	case 'f':
+		if len(b) == 4 {
+			return float64(math.Float32frombits(uint32(bits)))
+		}
+		return math.Float64frombits(bits)
+	case 'i':
+		// Sign-extend from the stored width
+		shift := 64 - 8*uint(len(b))
+		return float64(int64(bits<<shift) >> shift)
+	case 'b':
+		if bits != 0 {
+			return 1
+		}
+		return 0
+	default:
+		return float64(bits)
+	}
+}
+
+func WriteNPY(w io.Writer, m Matrix) error {
+	return WriteNPYOptions(w, m, NPYOptions{})
+}
+
+// WriteNPYOptions encodes m as a 2-d .npy array. Integer dtypes reject
+// values that are not whole numbers within range, so a write never loses
+// information silently.
+func WriteNPYOptions(w io.Writer, m Matrix, opts NPYOptions) error {
+	descr := opts.DType
+	if descr == "" {
+		descr = "<f8"
+	}
+	order, kind, size, err := parseDType(descr)
+	if err != nil {
+		return err
+	}
+	if kind == 'b' {
+		return fmt.Errorf("writing dtype %q is not supported", descr)
+	}
+
+	fortran := "False"
+	if opts.FortranOrder {
+		fortran = "True"
+	}
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': %s, 'shape': (%d, %d), }", descr, fortran, m.Rows, m.Cols)
+
+	// Pad so the data starts on a 64-byte boundary
+	total := len(npyMagic) + 2 + 2 + len(header) + 1
+	header += strings.Repeat(" ", (64-total%64)%64) + "\n"
+	if len(header) > math.MaxUint16 {
+		return errors.New(".npy header too long")
+	}
+
+	preamble := append(append([]byte(nil), npyMagic...), 1, 0)
+	preamble = binary.LittleEndian.AppendUint16(preamble, uint16(len(header)))
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(preamble); err != nil {
+		return err
+	}
+	if _, err := bw.WriteString(header); err != nil {
+		return err
+	}
+
+	src := m
+	if opts.FortranOrder {
+		src = m.Transpose()
+	}
+
+	buf := make([]byte, size)
+	for i := 0; i < src.Rows; i++ {
+		for _, v := range src.Row(i) {
+			if err := encodeScalar(buf, v, order, kind); err != nil {
+				return err
+			}
+			if _, err := bw.Write(buf); err != nil {
+				return err
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+func encodeScalar(b []byte, v float64, order binary.ByteOrder, kind byte) error {
+	var bits uint64
+	width := uint(len(b)) * 8
+
+	switch kind {
+	case 'f':
+		if len(b) == 4 {
+			bits = uint64(math.Float32bits(float32(v)))
+		} else {
+			bits = math.Float64bits(v)
+		}
+	case 'i':
+		limit := math.Ldexp(1, int(width)-1)
+		if v != math.Trunc(v) || v < -limit || v >= limit {
+			return fmt.Errorf("value %v does not fit in a %d-bit signed integer", v, width)
+		}
+		bits = uint64(int64(v))
+	case 'u':
+		limit := math.Ldexp(1, int(width))
+		if v != math.Trunc(v) || v < 0 || v >= limit {
+			return fmt.Errorf("value %v does not fit in a %d-bit unsigned integer", v, width)
+		}
+		bits = uint64(v)
+	}
+
+	switch len(b) {
+	case 1:
+		b[0] = byte(bits)
+	case 2:
+		order.PutUint16(b, uint16(bits))
+	case 4:
+		order.PutUint32(b, uint32(bits))
+	case 8:
+		order.PutUint64(b, bits)
+	}
+
+	return nil
+}
+
+func LoadNPY(path string) (Matrix, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Matrix{}, err
+	}
+	defer f.Close()
+
+	return ReadNPY(f)
+}
+
+func SaveNPY(path string, m Matrix) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := WriteNPY(f, m); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// LoadNPZ reads every array of a .npz archive, keyed by name without the
+// .npy suffix. Both stored and deflated archives are accepted.
+func LoadNPZ(path string) (map[string]Matrix, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	arrays := make(map[string]Matrix, len(zr.File))
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		m, err := ReadNPY(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+
+		arrays[strings.TrimSuffix(file.Name, ".npy")] = m
+	}
+
+	return arrays, nil
+}
+
+// SaveNPZ writes an uncompressed archive, like numpy.savez.
+func SaveNPZ(path string, arrays map[string]Matrix) error {
+	return saveNPZ(path, arrays, zip.Store)
+}
+
+// SaveNPZCompressed writes a deflated archive, like numpy.savez_compressed.
+func SaveNPZCompressed(path string, arrays map[string]Matrix) error {
+	return saveNPZ(path, arrays, zip.Deflate)
+}
+
+func saveNPZ(path string, arrays map[string]Matrix, method uint16) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	names := make([]string, 0, len(arrays))
+	for name := range arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(f)
+	for _, name := range names {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name + ".npy", Method: method})
+		if err != nil {
+			return err
+		}
+		if err := WriteNPY(w, arrays[name]); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
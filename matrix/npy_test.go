@@ -0,0 +1,141 @@
+package matrix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestNPYRoundTrip(t *testing.T) {
+	m := Zeros(5, 3)
+	for i := range m.Data {
+		m.Data[i] = float64(i*7%23) - 11
+	}
+
+	options := []NPYOptions{
+		{},
+		{DType: "<f4"},
+		{DType: ">f8", FortranOrder: true},
+		{DType: "<i2"},
+		{DType: ">i8", FortranOrder: true},
+	}
+	for _, opts := range options {
+		var buf bytes.Buffer
+		if err := WriteNPYOptions(&buf, m, opts); err != nil {
+			t.Fatalf("%+v: %v", opts, err)
+		}
+		headerLen := int(binary.LittleEndian.Uint16(buf.Bytes()[8:10]))
+		if (10+headerLen)%64 != 0 {
+			t.Errorf("%+v: data starts at offset %d, not a multiple of 64", opts, 10+headerLen)
+		}
+
+		got, err := ReadNPY(&buf)
+		if err != nil {
+			t.Fatalf("%+v: %v", opts, err)
+		}
+		assertClose(t, got, m)
+	}
+}
+
+func TestNPYIntegerRange(t *testing.T) {
+	for _, v := range []float64{1.5, 300, -1} {
+		var buf bytes.Buffer
+		if err := WriteNPYOptions(&buf, New([][]float64{{v}}), NPYOptions{DType: "|u1"}); err == nil {
+			t.Errorf("%v written as |u1", v)
+		}
+	}
+}
+
+// npyHeader builds a version 1.0 preamble and header for hand-made inputs.
+func npyHeader(header string) []byte {
+	b := append([]byte(nil), npyMagic...)
+	b = append(b, 1, 0)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(header)))
+
+	return append(b, header...)
+}
+
+func TestReadNPYRejectsBadInput(t *testing.T) {
+	tests := map[string][]byte{
+		"not npy":   []byte("PK\x03\x04 something else"),
+		"no shape":  npyHeader("{'descr': '<f8', 'fortran_order': False, }"),
+		"dtype":     npyHeader("{'descr': '<c16', 'fortran_order': False, 'shape': (1,), }"),
+		"3-d":       npyHeader("{'descr': '<f8', 'fortran_order': False, 'shape': (2, 2, 2), }"),
+		"overflow":  npyHeader("{'descr': '<f8', 'fortran_order': False, 'shape': (9223372036854775807, 3), }"),
+		"truncated": append(npyHeader("{'descr': '<f8', 'fortran_order': False, 'shape': (100000000, 100), }"), "12345678"...),
+	}
+	for name, input := range tests {
+		if _, err := ReadNPY(bytes.NewReader(input)); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+
+	_, err := ReadNPY(bytes.NewReader(tests["truncated"]))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("truncated: err = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestReadNPYShapes(t *testing.T) {
+	data := make([]byte, 0, 24)
+	for _, v := range []float64{1, 2, 3} {
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+	}
+
+	vector, err := ReadNPY(bytes.NewReader(append(npyHeader("{'descr': '<f8', 'fortran_order': False, 'shape': (3,), }"), data...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vector.Rows != 3 || vector.Cols != 1 {
+		t.Errorf("1-d array loaded as %dx%d, want 3x1", vector.Rows, vector.Cols)
+	}
+
+	scalar, err := ReadNPY(bytes.NewReader(append(npyHeader("{'descr': '<f8', 'fortran_order': False, 'shape': (), }"), data[:8]...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scalar.Rows != 1 || scalar.Cols != 1 || scalar.At(0, 0) != 1 {
+		t.Errorf("0-d array loaded as %v", scalar)
+	}
+}
+
+func TestNPZRoundTrip(t *testing.T) {
+	arrays := map[string]Matrix{
+		"a": New([][]float64{{1, 2}, {3, 4}}),
+		"b": New([][]float64{{-1.5, 0, 2.25}}),
+	}
+
+	for _, save := range []func(string, map[string]Matrix) error{SaveNPZ, SaveNPZCompressed} {
+		path := filepath.Join(t.TempDir(), "arrays.npz")
+		if err := save(path, arrays); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := LoadNPZ(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(arrays) {
+			t.Fatalf("loaded %d arrays, want %d", len(got), len(arrays))
+		}
+		for name, m := range arrays {
+			assertClose(t, got[name], m)
+		}
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriteNPYReportsWriteErrors(t *testing.T) {
+	if err := WriteNPY(failingWriter{}, Zeros(2, 2)); err == nil {
+		t.Error("write error was dropped")
+	}
+}
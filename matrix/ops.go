@@ -0,0 +1,300 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrDimensionMismatch = errors.New("matrix dimensions do not match")
+	ErrInvalidAxis       = errors.New("axis must be AxisRows or AxisCols")
+	ErrEmptyAxis         = errors.New("cannot reduce along an empty axis")
+)
+
+// Axis selects the direction of a reduction. AxisRows collapses the rows
+// and yields one value per column; AxisCols collapses the columns and
+// yields one value per row.
+type Axis int
+
+const (
+	AxisRows Axis = iota
+	AxisCols
+)
+
+func (m Matrix) Add(n Matrix) (Matrix, error) {
+	return m.zip(n, func(a, b float64) float64 { return a + b })
+}
+
+func (m Matrix) Sub(n Matrix) (Matrix, error) {
+	return m.zip(n, func(a, b float64) float64 { return a - b })
+}
+
+func (m Matrix) Hadamard(n Matrix) (Matrix, error) {
+	return m.zip(n, func(a, b float64) float64 { return a * b })
+}
+
+func (m Matrix) Scale(s float64) Matrix {
+	return m.Apply(func(v float64) float64 { return v * s })
+}
+
+func (m Matrix) Apply(fn func(float64) float64) Matrix {
+	out := Zeros(m.Rows, m.Cols)
+
+	for i := 0; i < m.Rows; i++ {
+		src, dst := m.Row(i), out.Row(i)
+		for j, v := range src {
+			dst[j] = fn(v)
+		}
+	}
+
+	return out
+}
+
+func (m Matrix) zip(n Matrix, fn func(a, b float64) float64) (Matrix, error) {
+	if m.Rows != n.Rows || m.Cols != n.Cols {
+		return Matrix{}, ErrDimensionMismatch
+	}
+
+	out := Zeros(m.Rows, m.Cols)
+
+	for i := 0; i < m.Rows; i++ {
+		a, b, dst := m.Row(i), n.Row(i), out.Row(i)
+		for j := range dst {
+			dst[j] = fn(a[j], b[j])
+		}
+	}
+
+	return out, nil
+}
+
+// Row broadcasting: v has one entry per column and is applied to every row
+
+func (m Matrix) AddRowVector(v []float64) (Matrix, error) {
+	return m.broadcastRow(v, func(a, b float64) float64 { return a + b })
+}
+
+func (m Matrix) SubRowVector(v []float64) (Matrix, error) {
+	return m.broadcastRow(v, func(a, b float64) float64 { return a - b })
+}
+
+func (m Matrix) MulRowVector(v []float64) (Matrix, error) {
+	return m.broadcastRow(v, func(a, b float64) float64 { return a * b })
+}
+
+func (m Matrix) DivRowVector(v []float64) (Matrix, error) {
+	return m.broadcastRow(v, func(a, b float64) float64 { return a / b })
+}
+
+// Column broadcasting: v has one entry per row and is applied to every column
+
+func (m Matrix) AddColVector(v []float64) (Matrix, error) {
+	return m.broadcastCol(v, func(a, b float64) float64 { return a + b })
+}
+
+func (m Matrix) SubColVector(v []float64) (Matrix, error) {
+	return m.broadcastCol(v, func(a, b float64) float64 { return a - b })
+}
+
+func (m Matrix) MulColVector(v []float64) (Matrix, error) {
+	return m.broadcastCol(v, func(a, b float64) float64 { return a * b })
+}
+
+func (m Matrix) DivColVector(v []float64) (Matrix, error) {
+	return m.broadcastCol(v, func(a, b float64) float64 { return a / b })
+}
+
+func (m Matrix) broadcastRow(v []float64, fn func(a, b float64) float64) (Matrix, error) {
+	if len(v) != m.Cols {
+		return Matrix{}, ErrDimensionMismatch
+	}
+
+	out := Zeros(m.Rows, m.Cols)
+
+	for i := 0; i < m.Rows; i++ {
+		src, dst := m.Row(i), out.Row(i)
+		for j := range dst {
+			dst[j] = fn(src[j], v[j])
+		}
+	}
+
+	return out, nil
+}
+
+func (m Matrix) broadcastCol(v []float64, fn func(a, b float64) float64) (Matrix, error) {
+	if len(v) != m.Rows {
+		return Matrix{}, ErrDimensionMismatch
+	}
+
+	out := Zeros(m.Rows, m.Cols)
+
+	for i := 0; i < m.Rows; i++ {
+		src, dst := m.Row(i), out.Row(i)
+		for j := range dst {
+			dst[j] = fn(src[j], v[i])
+		}
+	}
+
+	return out, nil
+}
+
+// Reductions
+
+func (m Matrix) Sum() float64 {
+	sum := 0.0
+
+	for i := 0; i < m.Rows; i++ {
+		for _, v := range m.Row(i) {
+			sum += v
+		}
+	}
+
+	return sum
+}
+
+func (m Matrix) Mean() float64 {
+	return m.Sum() / float64(m.Rows*m.Cols)
+}
+
+func (m Matrix) SumAxis(axis Axis) ([]float64, error) {
+	return m.reduce(axis, 0, func(acc, v float64) float64 { return acc + v })
+}
+
+func (m Matrix) MeanAxis(axis Axis) ([]float64, error) {
+	sums, err := m.SumAxis(axis)
+	if err != nil {
+		return nil, err
+	}
+
+	n := float64(m.axisLen(axis))
+	for i := range sums {
+		sums[i] /= n
+	}
+
+	return sums, nil
+}
+
+// VarAxis returns the population variance (divisor n) along axis.
+func (m Matrix) VarAxis(axis Axis) ([]float64, error) {
+	means, err := m.MeanAxis(axis)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make([]float64, len(means))
+	for i := 0; i < m.Rows; i++ {
+		for j, v := range m.Row(i) {
+			if axis == AxisRows {
+				diff := v - means[j]
+				vars[j] += diff * diff
+			} else {
+				diff := v - means[i]
+				vars[i] += diff * diff
+			}
+		}
+	}
+
+	n := float64(m.axisLen(axis))
+	for i := range vars {
+		vars[i] /= n
+	}
+
+	return vars, nil
+}
+
+func (m Matrix) MinAxis(axis Axis) ([]float64, error) {
+	if err := m.checkReducible(axis); err != nil {
+		return nil, err
+	}
+
+	return m.reduce(axis, math.Inf(1), math.Min)
+}
+
+func (m Matrix) MaxAxis(axis Axis) ([]float64, error) {
+	if err := m.checkReducible(axis); err != nil {
+		return nil, err
+	}
+
+	return m.reduce(axis, math.Inf(-1), math.Max)
+}
+
+// ArgMaxAxis returns the index of the largest element along axis. Ties
+// resolve to the lowest index.
+func (m Matrix) ArgMaxAxis(axis Axis) ([]int, error) {
+	if err := m.checkReducible(axis); err != nil {
+		return nil, err
+	}
+
+	size := m.outLen(axis)
+	best := make([]float64, size)
+	idx := make([]int, size)
+	for k := range best {
+		best[k] = math.Inf(-1)
+	}
+
+	for i := 0; i < m.Rows; i++ {
+		for j, v := range m.Row(i) {
+			k, pos := j, i
+			if axis == AxisCols {
+				k, pos = i, j
+			}
+			if v > best[k] {
+				best[k] = v
+				idx[k] = pos
+			}
+		}
+	}
+
+	return idx, nil
+}
+
+func (m Matrix) reduce(axis Axis, init float64, fn func(acc, v float64) float64) ([]float64, error) {
+	if axis != AxisRows && axis != AxisCols {
+		return nil, ErrInvalidAxis
+	}
+
+	out := make([]float64, m.outLen(axis))
+	for k := range out {
+		out[k] = init
+	}
+
+	for i := 0; i < m.Rows; i++ {
+		for j, v := range m.Row(i) {
+			if axis == AxisRows {
+				out[j] = fn(out[j], v)
+			} else {
+				out[i] = fn(out[i], v)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func (m Matrix) checkReducible(axis Axis) error {
+	if axis != AxisRows && axis != AxisCols {
+		return ErrInvalidAxis
+	}
+	if m.axisLen(axis) == 0 {
+		return ErrEmptyAxis
+	}
+
+	return nil
+}
+
+// axisLen is the number of elements collapsed by a reduction along axis.
+func (m Matrix) axisLen(axis Axis) int {
+	if axis == AxisRows {
+		return m.Rows
+	}
+
+	return m.Cols
+}
+
+// outLen is the number of values a reduction along axis produces.
+func (m Matrix) outLen(axis Axis) int {
+	if axis == AxisRows {
+		return m.Cols
+	}
+
+	return m.Rows
+}
@@ -0,0 +1,164 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrRankDeficient = errors.New("matrix is rank deficient")
+
+// QRFactors holds a Householder factorization A = Q·R of an m×n matrix
+// with m >= n. The Householder vectors are stored below the diagonal and
+// the diagonal of R is kept separately.
+type QRFactors struct {
+	qr    Matrix
+	rDiag []float64
+}
+
+func QR(a Matrix) (*QRFactors, error) {
+	m, n := a.Rows, a.Cols
+	if m < n {
+		return nil, errors.New("QR requires at least as many rows as columns")
+	}
+
+	qr := a.Copy()
+	rDiag := make([]float64, n)
+
+	for k := 0; k < n; k++ {
+		nrm := 0.0
+		for i := k; i < m; i++ {
+			nrm = math.Hypot(nrm, qr.At(i, k))
+		}
+
+		if nrm != 0 {
+			// Reflect column k onto a multiple of e_k
+			if qr.At(k, k) < 0 {
+				nrm = -nrm
+			}
+			for i := k; i < m; i++ {
+				qr.Set(i, k, qr.At(i, k)/nrm)
+			}
+			qr.Set(k, k, qr.At(k, k)+1)
+
+			for j := k + 1; j < n; j++ {
+				s := 0.0
+				for i := k; i < m; i++ {
+					s += qr.At(i, k) * qr.At(i, j)
+				}
+				s = -s / qr.At(k, k)
+				for i := k; i < m; i++ {
+					qr.Set(i, j, qr.At(i, j)+s*qr.At(i, k))
+				}
+			}
+		}
+
+		rDiag[k] = -nrm
+	}
+
+	return &QRFactors{qr: qr, rDiag: rDiag}, nil
+}
+
+// IsFullRank reports whether every diagonal entry of R is non-zero
+// relative to the largest one.
+func (f *QRFactors) IsFullRank() bool {
+	maxDiag := 0.0
+	for _, d := range f.rDiag {
+		maxDiag = math.Max(maxDiag, math.Abs(d))
+	}
+
+	tol := float64(max(f.qr.Rows, f.qr.Cols)) * maxDiag * epsilon
+	for _, d := range f.rDiag {
+		if math.Abs(d) <= tol {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Q returns the thin m×n orthonormal factor.
+func (f *QRFactors) Q() Matrix {
+	m, n := f.qr.Rows, f.qr.Cols
+	q := Zeros(m, n)
+
+	for k := n - 1; k >= 0; k-- {
+		q.Set(k, k, 1)
+		for j := k; j < n; j++ {
+			if f.qr.At(k, k) == 0 {
+				continue
+			}
+			s := 0.0
+			for i := k; i < m; i++ {
+				s += f.qr.At(i, k) * q.At(i, j)
+			}
+			s = -s / f.qr.At(k, k)
+			for i := k; i < m; i++ {
+				q.Set(i, j, q.At(i, j)+s*f.qr.At(i, k))
+			}
+		}
+	}
+
+	return q
+}
+
+// R returns the n×n upper triangular factor.
+func (f *QRFactors) R() Matrix {
+	n := f.qr.Cols
+	r := Zeros(n, n)
+
+	for i := 0; i < n; i++ {
+		r.Set(i, i, f.rDiag[i])
+		for j := i + 1; j < n; j++ {
+			r.Set(i, j, f.qr.At(i, j))
+		}
+	}
+
+	return r
+}
+
+// Solve returns the least-squares solution X minimizing ‖A·X - B‖.
+func (f *QRFactors) Solve(b Matrix) (Matrix, error) {
+	m, n := f.qr.Rows, f.qr.Cols
+	if b.Rows != m {
+		return Matrix{}, ErrDimensionMismatch
+	}
+	if !f.IsFullRank() {
+		return Matrix{}, ErrRankDeficient
+	}
+
+	x := b.Copy()
+
+	// Apply Qᵀ to B
+	for k := 0; k < n; k++ {
+		for j := 0; j < x.Cols; j++ {
+			s := 0.0
+			for i := k; i < m; i++ {
+				s += f.qr.At(i, k) * x.At(i, j)
+			}
+			s = -s / f.qr.At(k, k)
+			for i := k; i < m; i++ {
+				x.Set(i, j, x.At(i, j)+s*f.qr.At(i, k))
+			}
+		}
+	}
+
+	// Back substitution with R
+	for k := n - 1; k >= 0; k-- {
+		xk := x.Row(k)
+		for j := range xk {
+			xk[j] /= f.rDiag[k]
+		}
+		for i := 0; i < k; i++ {
+			rik := f.qr.At(i, k)
+			xi := x.Row(i)
+			for j := range xi {
+				xi[j] -= xk[j] * rik
+			}
+		}
+	}
+
+	out := Zeros(n, x.Cols)
+	copy(out.Data, x.Data[:n*x.Stride])
+
+	return out, nil
+}
@@ -0,0 +1,112 @@
+package matrix
+
+import "math"
+
+// Solve returns X with A·X = B. Square systems are solved with LU; tall
+// systems are solved in the least-squares sense with QR.
+func Solve(a, b Matrix) (Matrix, error) {
+	if a.Rows == a.Cols {
+		lu, err := LU(a)
+		if err != nil {
+			return Matrix{}, err
+		}
+
+		return lu.Solve(b)
+	}
+
+	qr, err := QR(a)
+	if err != nil {
+		return Matrix{}, err
+	}
+
+	return qr.Solve(b)
+}
+
+func Det(a Matrix) (float64, error) {
+	lu, err := LU(a)
+	if err != nil {
+		return 0, err
+	}
+
+	return lu.Det(), nil
+}
+
+// Cond estimates the 1-norm condition number ‖A‖₁·‖A⁻¹‖₁ using Hager's
+// method, which needs only a handful of solves against the LU factors.
+// Singular matrices report +Inf.
+func Cond(a Matrix) (float64, error) {
+	lu, err := LU(a)
+	if err != nil {
+		return 0, err
+	}
+	if lu.IsSingular() {
+		return math.Inf(1), nil
+	}
+
+	n := a.Rows
+	if n == 0 {
+		return 0, nil
+	}
+
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = 1 / float64(n)
+	}
+
+	invNorm := 0.0
+	for iter := 0; iter < 5; iter++ {
+		xm, _ := NewFromSlice(n, 1, x)
+		ym, err := lu.Solve(xm)
+		if err != nil {
+			return 0, err
+		}
+		y := ym.Data
+
+		invNorm = 0
+		xi := make([]float64, n)
+		for i, v := range y {
+			invNorm += math.Abs(v)
+			if v >= 0 {
+				xi[i] = 1
+			} else {
+				xi[i] = -1
+			}
+		}
+
+		z := lu.solveTransposeVec(xi)
+		best, zx := 0, 0.0
+		for i, v := range z {
+			zx += v * x[i]
+			if math.Abs(v) > math.Abs(z[best]) {
+				best = i
+			}
+		}
+		if math.Abs(z[best]) <= zx {
+			break
+		}
+
+		for i := range x {
+			x[i] = 0
+		}
+		x[best] = 1
+	}
+
+	return norm1(a) * invNorm, nil
+}
+
+// norm1 is the maximum absolute column sum.
+func norm1(a Matrix) float64 {
+	sums := make([]float64, a.Cols)
+	for i := 0; i < a.Rows; i++ {
+		for j, v := range a.Row(i) {
+			sums[j] += math.Abs(v)
+		}
+	}
+
+	best := 0.0
+	for _, s := range sums {
+		best = math.Max(best, s)
+	}
+
+	return best
+}
@@ -0,0 +1,359 @@
+package matrix
+
+import (
+	"errors"
+	"sort"
+)
+
+// Mat is the read-only view shared by dense and sparse matrices so models
+// can accept either.
+type Mat interface {
+	Dims() (rows, cols int)
+	At(i, j int) float64
+	// DoRowNonZero calls fn for every stored non-zero of row i in
+	// increasing column order.
+	DoRowNonZero(i int, fn func(j int, v float64))
+}
+
+func (m Matrix) Dims() (rows, cols int) {
+	return m.Rows, m.Cols
+}
+
+func (m Matrix) DoRowNonZero(i int, fn func(j int, v float64)) {
+	for j, v := range m.Row(i) {
+		if v != 0 {
+			fn(j, v)
+		}
+	}
+}
+
+type SparseFormat int
+
+const (
+	CSR SparseFormat = iota
+	CSC
+)
+
+// Sparse is a compressed sparse matrix. In CSR form Indptr has Rows+1
+// entries and the non-zeros of row i are Indices/Values[Indptr[i]:Indptr[i+1]]
+// with Indices holding column numbers; CSC swaps the roles of rows and
+// columns. Indices are sorted within each row (or column).
+type Sparse struct {
+	Format  SparseFormat
+	Rows    int
+	Cols    int
+	Indptr  []int
+	Indices []int
+	Values  []float64
+}
+
+func NewSparse(format SparseFormat, rows, cols int, indptr, indices []int, values []float64) (*Sparse, error) {
+	major, minor := rows, cols
+	if format == CSC {
+		major, minor = cols, rows
+	}
+
+	if len(indptr) != major+1 || indptr[0] != 0 || indptr[major] != len(indices) {
+		return nil, errors.New("indptr does not describe the index array")
+	}
+	if len(indices) != len(values) {
+		return nil, errors.New("indices and values must have the same length")
+	}
+	for k := 0; k < major; k++ {
+		if indptr[k] > indptr[k+1] {
+			return nil, errors.New("indptr must be non-decreasing")
+		}
+		for p := indptr[k]; p < indptr[k+1]; p++ {
+			if indices[p] < 0 || indices[p] >= minor {
+				return nil, errors.New("sparse index out of range")
+			}
+			if p > indptr[k] && indices[p] <= indices[p-1] {
+				return nil, errors.New("sparse indices must be strictly increasing")
+			}
+		}
+	}
+
+	return &Sparse{Format: format, Rows: rows, Cols: cols, Indptr: indptr, Indices: indices, Values: values}, nil
+}
+
+// NewSparseFromTriplets builds a sparse matrix from coordinate entries,
+// summing duplicates and dropping explicit zeros.
+func NewSparseFromTriplets(format SparseFormat, rows, cols int, ri, ci []int, values []float64) (*Sparse, error) {
+	if len(ri) != len(ci) || len(ri) != len(values) {
+		return nil, errors.New("triplet slices must have the same length")
+	}
+
+	major, minor := ri, ci
+	nMajor := rows
+	if format == CSC {
+		major, minor = ci, ri
+		nMajor = cols
+	}
+
+	order := make([]int, len(values))
+	for k := range order {
+		if ri[k] < 0 || ri[k] >= rows || ci[k] < 0 || ci[k] >= cols {
+			return nil, errors.New("sparse index out of range")
+		}
+		order[k] = k
+	}
+	sort.Slice(order, func(a, b int) bool {
+		ka, kb := order[a], order[b]
+		if major[ka] != major[kb] {
+			return major[ka] < major[kb]
+		}
+		return minor[ka] < minor[kb]
+	})
+
+	s := &Sparse{Format: format, Rows: rows, Cols: cols, Indptr: make([]int, nMajor+1)}
+	for idx, k := range order {
+		if idx > 0 {
+			prev := order[idx-1]
+			if major[prev] == major[k] && minor[prev] == minor[k] {
+				s.Values[len(s.Values)-1] += values[k]
+				continue
+			}
+		}
+		s.Indices = append(s.Indices, minor[k])
+		s.Values = append(s.Values, values[k])
+		s.Indptr[major[k]+1]++
+	}
+	for k := 0; k < nMajor; k++ {
+		s.Indptr[k+1] += s.Indptr[k]
+	}
+
+	return s.dropZeros(), nil
+}
+
+func SparseFromDense(m Matrix, format SparseFormat) *Sparse {
+	s := &Sparse{Format: CSR, Rows: m.Rows, Cols: m.Cols, Indptr: make([]int, m.Rows+1)}
+
+	for i := 0; i < m.Rows; i++ {
+		for j, v := range m.Row(i) {
+			if v != 0 {
+				s.Indices = append(s.Indices, j)
+				s.Values = append(s.Values, v)
+			}
+		}
+		s.Indptr[i+1] = len(s.Indices)
+	}
+
+	if format == CSC {
+		return s.ToCSC()
+	}
+
+	return s
+}
+
+func (s *Sparse) dropZeros() *Sparse {
+	major := len(s.Indptr) - 1
+	write := 0
+	start := 0
+
+	for k := 0; k < major; k++ {
+		end := s.Indptr[k+1]
+		for p := start; p < end; p++ {
+			if s.Values[p] != 0 {
+				s.Indices[write] = s.Indices[p]
+				s.Values[write] = s.Values[p]
+				write++
+			}
+		}
+		start = end
+		s.Indptr[k+1] = write
+	}
+	s.Indices = s.Indices[:write]
+	s.Values = s.Values[:write]
+
+	return s
+}
+
+func (s *Sparse) Dims() (rows, cols int) {
+	return s.Rows, s.Cols
+}
+
+func (s *Sparse) NNZ() int {
+	return len(s.Values)
+}
+
+func (s *Sparse) At(i, j int) float64 {
+	major, minor := i, j
+	if s.Format == CSC {
+		major, minor = j, i
+	}
+
+	lo, hi := s.Indptr[major], s.Indptr[major+1]
+	p := lo + sort.SearchInts(s.Indices[lo:hi], minor)
+	if p < hi && s.Indices[p] == minor {
+		return s.Values[p]
+	}
+
+	return 0
+}
+
+// DoRowNonZero is cheap for CSR. For CSC it probes every column, so convert
+// with ToCSR before iterating rows repeatedly.
+func (s *Sparse) DoRowNonZero(i int, fn func(j int, v float64)) {
+	if s.Format == CSR {
+		for p := s.Indptr[i]; p < s.Indptr[i+1]; p++ {
+			fn(s.Indices[p], s.Values[p])
+		}
+		return
+	}
+
+	for j := 0; j < s.Cols; j++ {
+		if v := s.At(i, j); v != 0 {
+			fn(j, v)
+		}
+	}
+}
+
+func (s *Sparse) ToDense() Matrix {
+	out := Zeros(s.Rows, s.Cols)
+	major := len(s.Indptr) - 1
+
+	for k := 0; k < major; k++ {
+		for p := s.Indptr[k]; p < s.Indptr[k+1]; p++ {
+			if s.Format == CSR {
+				out.Set(k, s.Indices[p], s.Values[p])
+			} else {
+				out.Set(s.Indices[p], k, s.Values[p])
+			}
+		}
+	}
+
+	return out
+}
+
+func (s *Sparse) ToCSR() *Sparse {
+	if s.Format == CSR {
+		return s
+	}
+
+	return s.recompress(CSR)
+}
+
+func (s *Sparse) ToCSC() *Sparse {
+	if s.Format == CSC {
+		return s
+	}
+
+	return s.recompress(CSC)
+}
+
+// recompress switches between CSR and CSC with a counting sort, which keeps
+// the new minor indices ordered.
+func (s *Sparse) recompress(format SparseFormat) *Sparse {
+	nOld := len(s.Indptr) - 1
+	nNew := s.Rows
+	if format == CSC {
+		nNew = s.Cols
+	}
+
+	out := &Sparse{
+		Format:  format,
+		Rows:    s.Rows,
+		Cols:    s.Cols,
+		Indptr:  make([]int, nNew+1),
+		Indices: make([]int, len(s.Indices)),
+		Values:  make([]float64, len(s.Values)),
+	}
+
+	for _, idx := range s.Indices {
+		out.Indptr[idx+1]++
+	}
+	for k := 0; k < nNew; k++ {
+		out.Indptr[k+1] += out.Indptr[k]
+	}
+
+	next := append([]int(nil), out.Indptr[:nNew]...)
+	for k := 0; k < nOld; k++ {
+		for p := s.Indptr[k]; p < s.Indptr[k+1]; p++ {
+			dst := next[s.Indices[p]]
+			out.Indices[dst] = k
+			out.Values[dst] = s.Values[p]
+			next[s.Indices[p]]++
+		}
+	}
+
+	return out
+}
+
+// T returns the transpose, sharing storage with s: a CSR matrix read as CSC
+// is its own transpose.
+func (s *Sparse) T() *Sparse {
+	format := CSC
+	if s.Format == CSC {
+		format = CSR
+	}
+
+	return &Sparse{Format: format, Rows: s.Cols, Cols: s.Rows, Indptr: s.Indptr, Indices: s.Indices, Values: s.Values}
+}
+
+// Dot returns the dense product s·n.
+func (s *Sparse) Dot(n Matrix) (Matrix, error) {
+	if s.Cols != n.Rows {
+		return Matrix{}, errors.New("matrix dimensions do not match for dot product")
+	}
+
+	out := Zeros(s.Rows, n.Cols)
+	major := len(s.Indptr) - 1
+
+	for k := 0; k < major; k++ {
+		for p := s.Indptr[k]; p < s.Indptr[k+1]; p++ {
+			i, j := k, s.Indices[p]
+			if s.Format == CSC {
+				i, j = j, k
+			}
+			v := s.Values[p]
+			dst, src := out.Row(i), n.Row(j)
+			for c := range dst {
+				dst[c] += v * src[c]
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// DotSparse returns the dense product m·s.
+func (m Matrix) DotSparse(s *Sparse) (Matrix, error) {
+	if m.Cols != s.Rows {
+		return Matrix{}, errors.New("matrix dimensions do not match for dot product")
+	}
+
+	// (m·s)ᵀ = sᵀ·mᵀ, and sᵀ comes for free
+	t, err := s.T().Dot(m.Transpose())
+	if err != nil {
+		return Matrix{}, err
+	}
+
+	return t.Transpose(), nil
+}
+
+func (s *Sparse) MulVec(x []float64) ([]float64, error) {
+	if len(x) != s.Cols {
+		return nil, ErrDimensionMismatch
+	}
+
+	xm, _ := NewFromSlice(len(x), 1, x)
+	out, err := s.Dot(xm)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Data, nil
+}
+
+// RowMajor returns X in a form whose DoRowNonZero is cheap, converting CSC
+// input to CSR.
+func RowMajor(X Mat) Mat {
+	if s, ok := X.(*Sparse); ok {
+		return s.ToCSR()
+	}
+
+	return X
+}
+
+var _ Mat = Matrix{}
+var _ Mat = (*Sparse)(nil)
@@ -0,0 +1,286 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+var ErrNoConvergence = errors.New("iteration did not converge")
+
+type SVDKind int
+
+const (
+	// SVDThin returns U as m×k and V as n×k with k = min(m, n).
+	SVDThin SVDKind = iota
+	// SVDFull returns square U (m×m) and V (n×n).
+	SVDFull
+)
+
+// SVDFactors holds A = U·diag(S)·Vᵀ with S sorted in decreasing order.
+type SVDFactors struct {
+	U Matrix
+	S []float64
+	V Matrix
+}
+
+const maxJacobiSweeps = 60
+
+// SVD computes the singular value decomposition with one-sided Jacobi
+// rotations, which stays accurate for small singular values.
+func SVD(a Matrix, kind SVDKind) (*SVDFactors, error) {
+	if a.Rows < a.Cols {
+		f, err := SVD(a.Transpose(), kind)
+		if err != nil {
+			return nil, err
+		}
+
+		return &SVDFactors{U: f.V, S: f.S, V: f.U}, nil
+	}
+
+	m, n := a.Rows, a.Cols
+
+	// Work on columns stored as rows so each rotation touches contiguous memory
+	w := a.Transpose()
+	v := Identity(n)
+
+	converged := false
+	for sweep := 0; sweep < maxJacobiSweeps && !converged; sweep++ {
+		converged = true
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				wp, wq := w.Row(p), w.Row(q)
+				alpha, beta, gamma := 0.0, 0.0, 0.0
+				for i := 0; i < m; i++ {
+					alpha += wp[i] * wp[i]
+					beta += wq[i] * wq[i]
+					gamma += wp[i] * wq[i]
+				}
+
+				if gamma == 0 || math.Abs(gamma) <= epsilon*math.Sqrt(alpha*beta) {
+					continue
+				}
+				converged = false
+
+				zeta := (beta - alpha) / (2 * gamma)
+				t := math.Copysign(1, zeta) / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				c := 1 / math.Sqrt(1+t*t)
+				s := c * t
+
+				rotate(wp, wq, c, s)
+				rotate(v.Row(p), v.Row(q), c, s)
+			}
+		}
+	}
+	if !converged {
+		return nil, ErrNoConvergence
+	}
+
+	// Singular values are the column norms; normalizing gives U
+	sv := make([]float64, n)
+	for j := 0; j < n; j++ {
+		row := w.Row(j)
+		nrm := 0.0
+		for _, x := range row {
+			nrm = math.Hypot(nrm, x)
+		}
+		sv[j] = nrm
+		if nrm > 0 {
+			for i := range row {
+				row[i] /= nrm
+			}
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return sv[order[i]] > sv[order[j]] })
+
+	// u and vt hold the singular vectors as rows
+	uCols := n
+	if kind == SVDFull {
+		uCols = m
+	}
+	u := Zeros(uCols, m)
+	vt := Zeros(n, n)
+	s := make([]float64, n)
+	smax := 0.0
+	if n > 0 {
+		smax = sv[order[0]]
+	}
+	for k, j := range order {
+		s[k] = sv[j]
+		copy(vt.Row(k), v.Row(j))
+		if sv[j] > float64(m)*smax*epsilon {
+			copy(u.Row(k), w.Row(j))
+		}
+	}
+	completeBasis(u)
+
+	return &SVDFactors{U: u.Transpose(), S: s, V: vt.Transpose()}, nil
+}
+
+func rotate(x, y []float64, c, s float64) {
+	for i := range x {
+		xi := x[i]
+		x[i] = c*xi - s*y[i]
+		y[i] = s*xi + c*y[i]
+	}
+}
+
+// completeBasis replaces the zero rows of b with unit vectors orthogonal to
+// every other row, using Gram-Schmidt against the standard basis.
+func completeBasis(b Matrix) {
+	dim := b.Cols
+	next := 0
+
+	for r := 0; r < b.Rows; r++ {
+		row := b.Row(r)
+		if vecNorm(row) > 0 {
+			continue
+		}
+
+		for ; next < dim; next++ {
+			for i := range row {
+				row[i] = 0
+			}
+			row[next] = 1
+
+			// Orthogonalize twice for stability
+			for pass := 0; pass < 2; pass++ {
+				for o := 0; o < b.Rows; o++ {
+					if o == r {
+						continue
+					}
+					other := b.Row(o)
+					d := 0.0
+					for i := range row {
+						d += row[i] * other[i]
+					}
+					for i := range row {
+						row[i] -= d * other[i]
+					}
+				}
+			}
+
+			if nrm := vecNorm(row); nrm > 1e-8 {
+				for i := range row {
+					row[i] /= nrm
+				}
+				next++
+				break
+			}
+		}
+	}
+}
+
+func vecNorm(x []float64) float64 {
+	nrm := 0.0
+	for _, v := range x {
+		nrm = math.Hypot(nrm, v)
+	}
+
+	return nrm
+}
+
+// defaultTol mirrors the usual max(m, n)·σ₁·eps cut-off.
+func (f *SVDFactors) defaultTol() float64 {
+	if len(f.S) == 0 {
+		return 0
+	}
+
+	return float64(max(f.U.Rows, f.V.Rows)) * f.S[0] * epsilon
+}
+
+// Rank counts singular values above tol. A tol <= 0 selects the default
+// max(m, n)·σ₁·eps.
+func (f *SVDFactors) Rank(tol float64) int {
+	if tol <= 0 {
+		tol = f.defaultTol()
+	}
+
+	rank := 0
+	for _, s := range f.S {
+		if s > tol {
+			rank++
+		}
+	}
+
+	return rank
+}
+
+// PseudoInverse returns the Moore-Penrose inverse V·diag(1/S)·Uᵀ, treating
+// singular values at or below tol as zero.
+func (f *SVDFactors) PseudoInverse(tol float64) Matrix {
+	if tol <= 0 {
+		tol = f.defaultTol()
+	}
+
+	m, n := f.U.Rows, f.V.Rows
+	pinv := Zeros(n, m)
+
+	for k, s := range f.S {
+		if s <= tol {
+			continue
+		}
+		inv := 1 / s
+		for i := 0; i < n; i++ {
+			vik := f.V.At(i, k) * inv
+			if vik == 0 {
+				continue
+			}
+			row := pinv.Row(i)
+			for j := 0; j < m; j++ {
+				row[j] += vik * f.U.At(j, k)
+			}
+		}
+	}
+
+	return pinv
+}
+
+func Rank(a Matrix, tol float64) (int, error) {
+	f, err := SVD(a, SVDThin)
+	if err != nil {
+		return 0, err
+	}
+
+	return f.Rank(tol), nil
+}
+
+func PseudoInverse(a Matrix, tol float64) (Matrix, error) {
+	f, err := SVD(a, SVDThin)
+	if err != nil {
+		return Matrix{}, err
+	}
+
+	return f.PseudoInverse(tol), nil
+}
+
+// LstSq returns the least-squares solution of A·X = B. Full-rank systems
+// use QR; rank-deficient or wide systems fall back to the minimum-norm
+// solution from the pseudo-inverse.
+func LstSq(a, b Matrix) (Matrix, error) {
+	if a.Rows != b.Rows {
+		return Matrix{}, ErrDimensionMismatch
+	}
+
+	if a.Rows >= a.Cols {
+		qr, err := QR(a)
+		if err != nil {
+			return Matrix{}, err
+		}
+		if qr.IsFullRank() {
+			return qr.Solve(b)
+		}
+	}
+
+	pinv, err := PseudoInverse(a, 0)
+	if err != nil {
+		return Matrix{}, err
+	}
+
+	return pinv.Dot(b)
+}
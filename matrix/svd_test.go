@@ -0,0 +1,88 @@
+package matrix
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// reconstruct returns U·diag(S)·Vᵀ using the first len(S) columns.
+func reconstruct(f *SVDFactors) Matrix {
+	out := Zeros(f.U.Rows, f.V.Rows)
+	for k, s := range f.S {
+		for i := 0; i < out.Rows; i++ {
+			for j := 0; j < out.Cols; j++ {
+				out.Data[i*out.Stride+j] += f.U.At(i, k) * s * f.V.At(j, k)
+			}
+		}
+	}
+
+	return out
+}
+
+func TestSVD(t *testing.T) {
+	rng := rand.New(rand.NewSource(20))
+
+	shapes := [][2]int{{7, 4}, {4, 7}, {5, 5}}
+	for _, sh := range shapes {
+		a := randomMatrix(rng, sh[0], sh[1])
+		k := min(sh[0], sh[1])
+
+		thin, err := SVD(a, SVDThin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if thin.U.Cols != k || thin.V.Cols != k || len(thin.S) != k {
+			t.Fatalf("%dx%d thin: U %dx%d, V %dx%d", sh[0], sh[1], thin.U.Rows, thin.U.Cols, thin.V.Rows, thin.V.Cols)
+		}
+		assertClose(t, reconstruct(thin), a)
+		for i := 1; i < k; i++ {
+			if thin.S[i] > thin.S[i-1] {
+				t.Fatalf("S not decreasing: %v", thin.S)
+			}
+		}
+
+		full, err := SVD(a, SVDFull)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, mustDot(t, full.U.Transpose(), full.U), Identity(sh[0]))
+		assertClose(t, mustDot(t, full.V.Transpose(), full.V), Identity(sh[1]))
+		assertClose(t, reconstruct(full), a)
+	}
+}
+
+func TestRankAndPseudoInverse(t *testing.T) {
+	// Third row is the sum of the first two
+	a := New([][]float64{{1, 2, 3}, {0, 1, 4}, {1, 3, 7}, {2, 4, 6}})
+
+	rank, err := Rank(a, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rank != 2 {
+		t.Errorf("rank = %d, want 2", rank)
+	}
+
+	pinv, err := PseudoInverse(a, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Moore-Penrose conditions A·A⁺·A = A and A⁺·A·A⁺ = A⁺
+	assertClose(t, mustDot(t, mustDot(t, a, pinv), a), a)
+	assertClose(t, mustDot(t, mustDot(t, pinv, a), pinv), pinv)
+}
+
+func TestLstSqRankDeficient(t *testing.T) {
+	// Duplicate columns: the minimum-norm solution splits the weight evenly
+	a := New([][]float64{{1, 1}, {2, 2}, {3, 3}})
+	b := New([][]float64{{2}, {4}, {6}})
+
+	x, err := LstSq(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(x.At(0, 0)-1) > 1e-9 || math.Abs(x.At(1, 0)-1) > 1e-9 {
+		t.Errorf("x = %v, want [1 1]", x.Data)
+	}
+}
@@ -0,0 +1,121 @@
+package matrix
+
+import "fmt"
+
+// Views returned by Slice, RowView and ColView share storage with the
+// receiver: writes through either are visible in both. SelectRows,
+// SelectCols, Copy, HStack and VStack always allocate.
+
+// Slice returns the view of rows [r0, r1) and columns [c0, c1).
+func (m Matrix) Slice(r0, r1, c0, c1 int) Matrix {
+	if r0 < 0 || r1 > m.Rows || r0 > r1 || c0 < 0 || c1 > m.Cols || c0 > c1 {
+		panic(fmt.Sprintf("matrix: slice [%d:%d, %d:%d] out of range for %dx%d", r0, r1, c0, c1, m.Rows, m.Cols))
+	}
+
+	// An empty view owns no storage, so it gets a compact stride: Row then
+	// yields empty slices instead of reaching past Data
+	view := Matrix{Rows: r1 - r0, Cols: c1 - c0, Stride: c1 - c0}
+	if view.Rows > 0 && view.Cols > 0 {
+		view.Stride = m.Stride
+		view.Data = m.Data[r0*m.Stride+c0 : (r1-1)*m.Stride+c1]
+	}
+
+	return view
+}
+
+// RowView returns row i as a 1×Cols view.
+func (m Matrix) RowView(i int) Matrix {
+	return m.Slice(i, i+1, 0, m.Cols)
+}
+
+// ColView returns column j as a Rows×1 view.
+func (m Matrix) ColView(j int) Matrix {
+	return m.Slice(0, m.Rows, j, j+1)
+}
+
+// Col returns a copy of column j.
+func (m Matrix) Col(j int) []float64 {
+	out := make([]float64, m.Rows)
+	for i := range out {
+		out[i] = m.At(i, j)
+	}
+
+	return out
+}
+
+// SelectRows copies the given rows, in order, into a new matrix. Indices
+// may repeat.
+func (m Matrix) SelectRows(indices []int) Matrix {
+	out := Zeros(len(indices), m.Cols)
+	for i, idx := range indices {
+		copy(out.Row(i), m.Row(idx))
+	}
+
+	return out
+}
+
+// SelectCols copies the given columns, in order, into a new matrix.
+func (m Matrix) SelectCols(indices []int) Matrix {
+	out := Zeros(m.Rows, len(indices))
+	for i := 0; i < m.Rows; i++ {
+		src, dst := m.Row(i), out.Row(i)
+		for j, idx := range indices {
+			dst[j] = src[idx]
+		}
+	}
+
+	return out
+}
+
+// HStack concatenates matrices with the same number of rows side by side.
+func HStack(ms ...Matrix) (Matrix, error) {
+	if len(ms) == 0 {
+		return Matrix{}, nil
+	}
+
+	cols := 0
+	for _, m := range ms {
+		if m.Rows != ms[0].Rows {
+			return Matrix{}, ErrDimensionMismatch
+		}
+		cols += m.Cols
+	}
+
+	out := Zeros(ms[0].Rows, cols)
+	for i := 0; i < out.Rows; i++ {
+		dst := out.Row(i)
+		offset := 0
+		for _, m := range ms {
+			copy(dst[offset:], m.Row(i))
+			offset += m.Cols
+		}
+	}
+
+	return out, nil
+}
+
+// VStack concatenates matrices with the same number of columns top to bottom.
+func VStack(ms ...Matrix) (Matrix, error) {
+	if len(ms) == 0 {
+		return Matrix{}, nil
+	}
+
+	rows := 0
+	for _, m := range ms {
+		if m.Cols != ms[0].Cols {
+			return Matrix{}, ErrDimensionMismatch
+		}
+		rows += m.Rows
+	}
+
+	out := Zeros(rows, ms[0].Cols)
+	offset := 0
+	for _, m := range ms {
+		for i := 0; i < m.Rows; i++ {
+			copy(out.Row(offset+i), m.Row(i))
+		}
+		offset += m.Rows
+	}
+
+	return out, nil
+}
@@ -2,12 +2,51 @@ package metrics
 
 import (
 	"math"
+
+	"golearn-lite/matrix"
 )
 
+// The metrics are written once over matrix.Float; the exported float64
+// functions keep plain signatures so they can be stored in variables and
+// maps, and metrics32.go has the float32 variants
+// (AccuracyFloat32 and so on).
+
+func Accuracy(yTrue, yPred []float64) float64 {
+	return accuracy(yTrue, yPred)
+}
+
+func Precision(yTrue, yPred []float64) float64 {
+	return precision(yTrue, yPred)
+}
+
+func Recall(yTrue, yPred []float64) float64 {
+	return recall(yTrue, yPred)
+}
+
+func CrossEntropy(yTrue, yPred []float64) float64 {
+	return crossEntropy(yTrue, yPred)
+}
+
+func MAE(yTrue, yPred []float64) float64 {
+	return mae(yTrue, yPred)
+}
+
+func MSE(yTrue, yPred []float64) float64 {
+	return mse(yTrue, yPred)
+}
+
+func RMSE(yTrue, yPred []float64) float64 {
+	return rmse(yTrue, yPred)
+}
+
+func R2(yTrue, yPred []float64) float64 {
+	return r2(yTrue, yPred)
+}
+
 
 // Classification Metrics
 
-func Accuracy(yTrue, yPred []float64) float64 {
+func accuracy[T matrix.Float](yTrue, yPred []T) float64 {
 	if len(yTrue) != len(yPred) || len(yTrue) == 0 {
 		return 0.0
 	}
@@ -22,7 +61,7 @@ func Accuracy(yTrue, yPred []float64) float64 {
 	return correct / float64(len(yTrue))
 }
 
-func Precision(yTrue, yPred []float64) float64 {
+func precision[T matrix.Float](yTrue, yPred []T) float64 {
 	var tp, fp float64
 
 	for i := range yTrue {
@@ -41,7 +80,7 @@ func Precision(yTrue, yPred []float64) float64 {
 	return tp / (tp + fp)
 }
 
-func Recall(yTrue, yPred []float64) float64 {
+func recall[T matrix.Float](yTrue, yPred []T) float64 {
 	var tp, fn float64
 
 	for i := range yTrue {
@@ -60,7 +99,7 @@ func Recall(yTrue, yPred []float64) float64 {
 	return tp / (tp + fn)
 }
 
-func CrossEntropy(yTrue, yPred []float64) float64 {
+func crossEntropy[T matrix.Float](yTrue, yPred []T) float64 {
 	var loss float64
 
 	n := len(yTrue)
@@ -70,7 +109,7 @@ func CrossEntropy(yTrue, yPred []float64) float64 {
 
 	eps := 1e-15
 	for i := 0; i < n; i++ {
-		pred := math.Max(eps, math.Min(1-eps, yPred[i]))
+		pred := math.Max(eps, math.Min(1-eps, float64(yPred[i])))
 		if int(yTrue[i]) == 1 {
 			loss -= math.Log(pred)
 		} else {
@@ -83,7 +122,7 @@ func CrossEntropy(yTrue, yPred []float64) float64 {
 
 // Regression Metrics
 
-func MAE(yTrue, yPred []float64) float64 {
+func mae[T matrix.Float](yTrue, yPred []T) float64 {
 	var sum float64
 	n := len(yTrue)
 	if n != len(yPred) || n == 0 {
@@ -91,13 +130,13 @@ func MAE(yTrue, yPred []float64) float64 {
 	}
 
 	for i := 0; i < n; i++ {
-		sum += math.Abs(yTrue[i] - yPred[i])
+		sum += math.Abs(float64(yTrue[i]) - float64(yPred[i]))
 	}
 
 	return sum / float64(n)
 }
 
-func MSE(yTrue, yPred []float64) float64 {
+func mse[T matrix.Float](yTrue, yPred []T) float64 {
 	var sum float64
 	n := len(yTrue)
 	if n != len(yPred) || n == 0 {
@@ -105,18 +144,18 @@ func MSE(yTrue, yPred []float64) float64 {
 	}
 
 	for i := 0; i < n; i++ {
-		diff := yTrue[i] - yPred[i]
+		diff := float64(yTrue[i]) - float64(yPred[i])
 		sum += diff * diff
 	}
 
 	return sum / float64(n)
 }
 
-func RMSE(yTrue, yPred []float64) float64 {
-	return math.Sqrt(MSE(yTrue, yPred))
+func rmse[T matrix.Float](yTrue, yPred []T) float64 {
+	return math.Sqrt(mse(yTrue, yPred))
 }
 
-func R2(yTrue, yPred []float64) float64 {
+func r2[T matrix.Float](yTrue, yPred []T) float64 {
 	var ssRes, ssTot, mean float64
 	n := len(yTrue)
 	if n != len(yPred) || n == 0 {
@@ -124,14 +163,14 @@ func R2(yTrue, yPred []float64) float64 {
 	}
 
 	for _, val := range yTrue {
-		mean += val
+		mean += float64(val)
 	}
 	mean /= float64(n)
 
 	for i := 0; i < n; i++ {
-		diff := yTrue[i] - yPred[i]
+		diff := float64(yTrue[i]) - float64(yPred[i])
 		ssRes += diff * diff
-		ssTot += (yTrue[i] - mean) * (yTrue[i] - mean)
+		ssTot += (float64(yTrue[i]) - mean) * (float64(yTrue[i]) - mean)
 	}
 
 	if ssTot == 0 {
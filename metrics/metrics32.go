@@ -0,0 +1,35 @@
+package metrics
+
+// float32 variants of the metrics, for predictions on Matrix32 data.
+
+func AccuracyFloat32(yTrue, yPred []float32) float64 {
+	return accuracy(yTrue, yPred)
+}
+
+func PrecisionFloat32(yTrue, yPred []float32) float64 {
+	return precision(yTrue, yPred)
+}
+
+func RecallFloat32(yTrue, yPred []float32) float64 {
+	return recall(yTrue, yPred)
+}
+
+func CrossEntropyFloat32(yTrue, yPred []float32) float64 {
+	return crossEntropy(yTrue, yPred)
+}
+
+func MAEFloat32(yTrue, yPred []float32) float64 {
+	return mae(yTrue, yPred)
+}
+
+func MSEFloat32(yTrue, yPred []float32) float64 {
+	return mse(yTrue, yPred)
+}
+
+func RMSEFloat32(yTrue, yPred []float32) float64 {
+	return rmse(yTrue, yPred)
+}
+
+func R2Float32(yTrue, yPred []float32) float64 {
+	return r2(yTrue, yPred)
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	// Plain function values, as callers store them
+	tests := []struct {
+		name     string
+		metric   func(yTrue, yPred []float64) float64
+		metric32 func(yTrue, yPred []float32) float64
+		yTrue    []float64
+		yPred    []float64
+		want     float64
+	}{
+		{"accuracy", Accuracy, AccuracyFloat32, []float64{1, 0, 1, 1}, []float64{1, 1, 1, 0}, 0.5},
+		{"precision", Precision, PrecisionFloat32, []float64{1, 0, 1, 1}, []float64{1, 1, 1, 0}, 2.0 / 3},
+		{"recall", Recall, RecallFloat32, []float64{1, 0, 1, 1}, []float64{1, 1, 1, 0}, 2.0 / 3},
+		{"mae", MAE, MAEFloat32, []float64{1, 2, 3}, []float64{2, 2, 1}, 1},
+		{"mse", MSE, MSEFloat32, []float64{1, 2, 3}, []float64{2, 2, 1}, 5.0 / 3},
+		{"rmse", RMSE, RMSEFloat32, []float64{1, 2, 3}, []float64{2, 2, 1}, math.Sqrt(5.0 / 3)},
+		{"r2", R2, R2Float32, []float64{1, 2, 3}, []float64{1, 2, 3}, 1},
+		{"cross entropy", CrossEntropy, CrossEntropyFloat32, []float64{1, 0}, []float64{0.5, 0.5}, math.Log(2)},
+	}
+
+	for _, tt := range tests {
+		if got := tt.metric(tt.yTrue, tt.yPred); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+
+		yTrue32, yPred32 := make([]float32, len(tt.yTrue)), make([]float32, len(tt.yPred))
+		for i := range tt.yTrue {
+			yTrue32[i], yPred32[i] = float32(tt.yTrue[i]), float32(tt.yPred[i])
+		}
+		if got := tt.metric32(yTrue32, yPred32); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("%s (float32) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
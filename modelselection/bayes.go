@@ -0,0 +1,734 @@
+package modelselection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"golearn-lite/core"
+	"golearn-lite/matrix"
+)
+
+// Space is the range of one hyperparameter for BayesSearchCV:
+// FloatSpace, IntSpace or CategoricalSpace.
+type Space interface {
+	validate() error
+	// restore converts a value decoded from a JSON trial history back to
+	// the type SetParams expects.
+	restore(v interface{}) (interface{}, error)
+}
+
+// FloatSpace is the float64 range [Low, High]; with Log it is searched on
+// a log scale and requires Low > 0.
+type FloatSpace struct {
+	Low, High float64
+	Log       bool
+}
+
+// IntSpace is the int range [Low, High]; with Log it is searched on a log
+// scale and requires Low >= 1.
+type IntSpace struct {
+	Low, High int
+	Log       bool
+}
+
+// CategoricalSpace is an unordered set of values of any type SetParams
+// accepts.
+type CategoricalSpace []interface{}
+
+// Trial states
+const (
+	TrialComplete = "complete"
+	TrialPruned   = "pruned"
+	TrialFailed   = "failed"
+)
+
+// ErrTrialPruned marks the results of trials stopped by median pruning.
+var ErrTrialPruned = errors.New("trial pruned")
+
+// Trial is one evaluated parameter set. Scores holds the metric of every
+// fold evaluated, fewer than the splitter's folds if the trial was pruned,
+// and Score their mean.
+type Trial struct {
+	Number   int                    `json:"number"`
+	Params   map[string]interface{} `json:"params"`
+	Scores   []float64              `json:"scores"`
+	Score    float64                `json:"score"`
+	State    string                 `json:"state"`
+	Error    string                 `json:"error,omitempty"`
+	Duration time.Duration          `json:"duration"`
+}
+
+// BayesSearchCV tunes hyperparameters with a tree-structured Parzen
+// estimator (TPE). After NStartup random trials, each new trial splits the
+// finished ones into the best Gamma fraction and the rest, fits a kernel
+// density to each group per parameter, and picks, among NCandidates draws
+// from the good density, the one with the highest good-to-bad density
+// ratio.
+//
+// Trials run one at a time until NTrials exist or Timeout has passed. With
+// Prune, a trial stops early once its running mean score after some fold
+// is worse than the median of earlier trials at that fold. With
+// HistoryPath set, trials are loaded from that JSON file if it exists and
+// the file is rewritten after each trial, so an interrupted search resumes
+// where it left off; NTrials counts the loaded trials too.
+type BayesSearchCV struct {
+	SearchCV
+	Space       map[string]Space
+	NTrials     int
+	Timeout     time.Duration // 0 means no limit
+	NStartup    int           // 0 means 10
+	NCandidates int           // 0 means 24
+	Gamma       float64       // 0 means 0.25
+	Prune       bool
+	HistoryPath string
+	RandomState *int64
+
+	Trials []Trial
+}
+
+func NewBayesSearchCV(estimator core.Model, space map[string]Space, nTrials int, cv Splitter, metric func(yTrue, yPred []float64) float64) *BayesSearchCV {
+	return &BayesSearchCV{
+		SearchCV: SearchCV{Estimator: estimator, CV: cv, Metric: metric, Refit: true},
+		Space:    space,
+		NTrials:  nTrials,
+	}
+}
+
+func (bs *BayesSearchCV) Fit(X matrix.Matrix, y []float64) error {
+	if err := bs.validate(); err != nil {
+		return err
+	}
+	if bs.NTrials < 1 {
+		return errors.New("NTrials must be at least 1")
+	}
+	if len(bs.Space) == 0 {
+		return errors.New("search space is empty")
+	}
+	for name, sp := range bs.Space {
+		if err := sp.validate(); err != nil {
+			return fmt.Errorf("parameter %q: %w", name, err)
+		}
+	}
+
+	folds, err := bs.CV.Split(X, y, bs.Groups)
+	if err != nil {
+		return err
+	}
+
+	bs.Trials = nil
+	if bs.HistoryPath != "" {
+		if bs.Trials, err = bs.loadHistory(); err != nil {
+			return err
+		}
+	}
+
+	// Offset the seed by the trials already run, so a resumed search does
+	// not draw the same startup configurations again
+	rng := core.RandFromState(bs.RandomState)
+	if bs.RandomState != nil && len(bs.Trials) > 0 {
+		rng = core.NewRand(*bs.RandomState + int64(len(bs.Trials)))
+	}
+	start := time.Now()
+
+	for len(bs.Trials) < bs.NTrials {
+		if bs.Timeout > 0 && time.Since(start) >= bs.Timeout {
+			break
+		}
+
+		trial := bs.runTrial(bs.suggest(rng), folds, X, y)
+		trial.Number = len(bs.Trials)
+		bs.Trials = append(bs.Trials, trial)
+
+		if bs.HistoryPath != "" {
+			if err := bs.saveHistory(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(bs.Trials) == 0 {
+		return errors.New("no trial finished before the timeout")
+	}
+
+	bs.Results = make([]CandidateResult, len(bs.Trials))
+	for k, t := range bs.Trials {
+		bs.Results[k] = t.result()
+	}
+
+	return bs.finish(X, y)
+}
+
+func (t Trial) result() CandidateResult {
+	res := CandidateResult{Params: t.Params, Scores: t.Scores, MeanScore: t.Score, StdScore: math.NaN()}
+	if len(t.Scores) > 0 {
+		res.MeanFitTime = t.Duration / time.Duration(len(t.Scores))
+	}
+
+	switch t.State {
+	case TrialFailed:
+		res.MeanScore = math.NaN()
+		res.Err = errors.New(t.Error)
+	case TrialPruned:
+		res.Err = ErrTrialPruned
+	}
+	if t.State == TrialComplete {
+		cv := CVResult{Scores: t.Scores}
+		res.StdScore = cv.Std()
+	}
+
+	return res
+}
+
+// runTrial cross-validates one parameter set. With Prune the folds run in
+// order so the trial can stop after any of them; otherwise they run NJobs
+// at a time.
+func (bs *BayesSearchCV) runTrial(params map[string]interface{}, folds []Fold, X matrix.Matrix, y []float64) Trial {
+	trial := Trial{Params: params, State: TrialComplete}
+	start := time.Now()
+
+	fail := func(err error) Trial {
+		trial.State, trial.Error = TrialFailed, err.Error()
+		trial.Duration = time.Since(start)
+		return trial
+	}
+
+	model, err := withParams(bs.Estimator, params)
+	if err != nil {
+		return fail(err)
+	}
+
+	if !bs.Prune {
+		scores := make([]float64, len(folds))
+		errs := make([]error, len(folds))
+		parallel(len(folds), bs.NJobs, func(k int) {
+			scores[k], errs[k] = bs.scoreFold(model, folds[k], X, y)
+		})
+		for k, err := range errs {
+			if err != nil {
+				return fail(fmt.Errorf("fold %d: %w", k, err))
+			}
+		}
+		trial.Scores, trial.Score = scores, meanOf(scores)
+		trial.Duration = time.Since(start)
+		return trial
+	}
+
+	for k, fold := range folds {
+		score, err := bs.scoreFold(model, fold, X, y)
+		if err != nil {
+			return fail(fmt.Errorf("fold %d: %w", k, err))
+		}
+		trial.Scores = append(trial.Scores, score)
+		trial.Score = meanOf(trial.Scores)
+
+		if k+1 < len(folds) && bs.shouldPrune(trial.Scores) {
+			trial.State = TrialPruned
+			break
+		}
+	}
+
+	trial.Duration = time.Since(start)
+	return trial
+}
+
+// scoreFold fits a clone of model on the training rows of fold and scores
+// it on the test rows.
+func (bs *BayesSearchCV) scoreFold(model core.Model, fold Fold, X matrix.Matrix, y []float64) (float64, error) {
+	m, err := core.Clone(model)
+	if err != nil {
+		return 0, err
+	}
+	if err := m.Fit(X.SelectRows(fold.Train), subset(y, fold.Train)); err != nil {
+		return 0, err
+	}
+	preds, err := core.Predict(m, X.SelectRows(fold.Test))
+	if err != nil {
+		return 0, err
+	}
+
+	score := bs.Metric(subset(y, fold.Test), preds)
+	if math.IsNaN(score) || math.IsInf(score, 0) {
+		return 0, fmt.Errorf("metric returned %v", score)
+	}
+
+	return score, nil
+}
+
+// shouldPrune compares the running mean after len(scores) folds with the
+// median running mean of earlier trials that got that far.
+func (bs *BayesSearchCV) shouldPrune(scores []float64) bool {
+	if !bs.Prune || len(bs.finished()) < bs.nStartup() {
+		return false
+	}
+
+	k := len(scores)
+	var others []float64
+	for _, t := range bs.Trials {
+		if t.State != TrialFailed && len(t.Scores) >= k {
+			others = append(others, bs.loss(meanOf(t.Scores[:k])))
+		}
+	}
+	if len(others) == 0 {
+		return false
+	}
+
+	sort.Float64s(others)
+	median := others[len(others)/2]
+	if len(others)%2 == 0 {
+		median = (others[len(others)/2-1] + median) / 2
+	}
+
+	return bs.loss(meanOf(scores)) > median
+}
+
+// loss maps a score to a value where lower is better.
+func (bs *BayesSearchCV) loss(score float64) float64 {
+	if bs.LowerIsBetter {
+		return score
+	}
+
+	return -score
+}
+
+// finished returns the trials TPE learns from: completed and pruned ones.
+func (bs *BayesSearchCV) finished() []Trial {
+	var out []Trial
+	for _, t := range bs.Trials {
+		if t.State != TrialFailed {
+			out = append(out, t)
+		}
+	}
+
+	return out
+}
+
+func (bs *BayesSearchCV) nStartup() int {
+	if bs.NStartup > 0 {
+		return bs.NStartup
+	}
+
+	return 10
+}
+
+// suggest draws the next parameter set: from the prior during startup and
+// from the TPE model afterwards.
+func (bs *BayesSearchCV) suggest(rng *rand.Rand) map[string]interface{} {
+	names := make([]string, 0, len(bs.Space))
+	for name := range bs.Space {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	finished := bs.finished()
+	if len(finished) < bs.nStartup() {
+		params := make(map[string]interface{}, len(names))
+		for _, name := range names {
+			params[name] = samplePrior(bs.Space[name], rng)
+		}
+		return params
+	}
+
+	// Split the finished trials into good (lowest loss) and bad
+	sort.SliceStable(finished, func(a, b int) bool {
+		return bs.loss(finished[a].Score) < bs.loss(finished[b].Score)
+	})
+	gamma := bs.Gamma
+	if gamma <= 0 || gamma >= 1 {
+		gamma = 0.25
+	}
+	nGood := max(1, int(math.Ceil(gamma*float64(len(finished)))))
+	good, bad := finished[:nGood], finished[nGood:]
+
+	nCandidates := bs.NCandidates
+	if nCandidates <= 0 {
+		nCandidates = 24
+	}
+
+	models := make([]*tpeModel, len(names))
+	for d, name := range names {
+		models[d] = newTPEModel(bs.Space[name], name, good, bad)
+	}
+
+	var best map[string]interface{}
+	bestRatio := math.Inf(-1)
+	for c := 0; c < nCandidates; c++ {
+		params := make(map[string]interface{}, len(names))
+		ratio := 0.0
+		for d, name := range names {
+			x := models[d].good.sample(rng)
+			ratio += models[d].good.logPDF(x) - models[d].bad.logPDF(x)
+			params[name] = models[d].value(x)
+		}
+		if ratio > bestRatio {
+			best, bestRatio = params, ratio
+		}
+	}
+
+	return best
+}
+
+// History file
+
+type trialHistory struct {
+	Trials []Trial `json:"trials"`
+}
+
+func (bs *BayesSearchCV) loadHistory() ([]Trial, error) {
+	b, err := os.ReadFile(bs.HistoryPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var h trialHistory
+	if err := json.Unmarshal(b, &h); err != nil {
+		return nil, fmt.Errorf("trial history %s: %w", bs.HistoryPath, err)
+	}
+
+	for k := range h.Trials {
+		t := &h.Trials[k]
+		if len(t.Params) != len(bs.Space) {
+			return nil, fmt.Errorf("trial history %s: trial %d does not match the search space", bs.HistoryPath, t.Number)
+		}
+		for name, v := range t.Params {
+			sp, ok := bs.Space[name]
+			if !ok {
+				return nil, fmt.Errorf("trial history %s: trial %d has parameter %q outside the search space", bs.HistoryPath, t.Number, name)
+			}
+			if t.Params[name], err = sp.restore(v); err != nil {
+				return nil, fmt.Errorf("trial history %s: trial %d, %q: %w", bs.HistoryPath, t.Number, name, err)
+			}
+		}
+	}
+
+	return h.Trials, nil
+}
+
+// saveHistory writes the history to a temporary file and renames it over
+// HistoryPath, so a crash never leaves a truncated history behind.
+func (bs *BayesSearchCV) saveHistory() error {
+	b, err := json.MarshalIndent(trialHistory{Trials: bs.Trials}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(bs.HistoryPath), filepath.Base(bs.HistoryPath)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), bs.HistoryPath)
+}
+
+// Spaces
+
+func (s FloatSpace) validate() error {
+	if !(s.Low < s.High) || (s.Log && s.Low <= 0) {
+		return fmt.Errorf("invalid float range [%v, %v] (log %v)", s.Low, s.High, s.Log)
+	}
+
+	return nil
+}
+
+func (s IntSpace) validate() error {
+	if s.Low > s.High || (s.Log && s.Low < 1) {
+		return fmt.Errorf("invalid int range [%d, %d] (log %v)", s.Low, s.High, s.Log)
+	}
+
+	return nil
+}
+
+func (s CategoricalSpace) validate() error {
+	if len(s) == 0 {
+		return errors.New("categorical space has no values")
+	}
+
+	return nil
+}
+
+func (s FloatSpace) restore(v interface{}) (interface{}, error) {
+	f, ok := v.(float64)
+	if !ok {
+		return nil, fmt.Errorf("expected a number, got %T", v)
+	}
+
+	return f, nil
+}
+
+func (s IntSpace) restore(v interface{}) (interface{}, error) {
+	f, ok := v.(float64)
+	if !ok || f != math.Trunc(f) {
+		return nil, fmt.Errorf("expected an integer, got %v", v)
+	}
+
+	return int(f), nil
+}
+
+// restore matches v against the choices by their JSON encoding, since
+// decoding loses the original Go type.
+func (s CategoricalSpace) restore(v interface{}) (interface{}, error) {
+	want, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, choice := range s {
+		got, err := json.Marshal(choice)
+		if err == nil && string(got) == string(want) {
+			return choice, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s is not one of the choices", want)
+}
+
+func samplePrior(sp Space, rng *rand.Rand) interface{} {
+	switch s := sp.(type) {
+	case CategoricalSpace:
+		return s[rng.Intn(len(s))]
+	}
+
+	lo, hi := numericBounds(sp)
+	return numericValue(sp, lo+rng.Float64()*(hi-lo))
+}
+
+// numericBounds is the range searched for a FloatSpace or IntSpace, in log
+// space where requested. Int ranges are widened by half a step on each
+// side so every integer has an equal share.
+func numericBounds(sp Space) (lo, hi float64) {
+	switch s := sp.(type) {
+	case FloatSpace:
+		lo, hi = s.Low, s.High
+		if s.Log {
+			return math.Log(lo), math.Log(hi)
+		}
+	case IntSpace:
+		lo, hi = float64(s.Low)-0.5, float64(s.High)+0.5
+		if s.Log {
+			return math.Log(lo), math.Log(hi)
+		}
+	}
+
+	return lo, hi
+}
+
+// numericPosition maps a parameter value into the searched range.
+func numericPosition(sp Space, v interface{}) float64 {
+	var x float64
+	log := false
+
+	switch s := sp.(type) {
+	case FloatSpace:
+		x, log = v.(float64), s.Log
+	case IntSpace:
+		x, log = float64(v.(int)), s.Log
+	}
+	if log {
+		return math.Log(x)
+	}
+
+	return x
+}
+
+// numericValue is the inverse of numericPosition, clamped to the space.
+func numericValue(sp Space, x float64) interface{} {
+	switch s := sp.(type) {
+	case FloatSpace:
+		if s.Log {
+			x = math.Exp(x)
+		}
+		return math.Min(math.Max(x, s.Low), s.High)
+	case IntSpace:
+		if s.Log {
+			x = math.Exp(x)
+		}
+		return min(max(int(math.Round(x)), s.Low), s.High)
+	}
+
+	return nil
+}
+
+// TPE densities
+
+// density is a one-dimensional distribution over a parameter's searched
+// range (numeric) or choice indices (categorical).
+type density interface {
+	sample(rng *rand.Rand) float64
+	logPDF(x float64) float64
+}
+
+type tpeModel struct {
+	space     Space
+	good, bad density
+}
+
+func newTPEModel(sp Space, name string, good, bad []Trial) *tpeModel {
+	m := &tpeModel{space: sp}
+
+	if cs, ok := sp.(CategoricalSpace); ok {
+		m.good = newCategoricalDensity(cs, name, good)
+		m.bad = newCategoricalDensity(cs, name, bad)
+		return m
+	}
+
+	lo, hi := numericBounds(sp)
+	positions := func(trials []Trial) []float64 {
+		xs := make([]float64, len(trials))
+		for k, t := range trials {
+			xs[k] = numericPosition(sp, t.Params[name])
+		}
+		return xs
+	}
+	m.good = newParzen(positions(good), lo, hi)
+	m.bad = newParzen(positions(bad), lo, hi)
+
+	return m
+}
+
+// value converts a draw from the densities into a parameter value.
+func (m *tpeModel) value(x float64) interface{} {
+	if cs, ok := m.space.(CategoricalSpace); ok {
+		return cs[int(x)]
+	}
+
+	return numericValue(m.space, x)
+}
+
+// parzen is a mixture of truncated Gaussians on [lo, hi], one per
+// observation plus a wide prior component, with each bandwidth set by the
+// distance to the neighbouring observations.
+type parzen struct {
+	mus, sigmas []float64
+	lo, hi      float64
+}
+
+func newParzen(obs []float64, lo, hi float64) *parzen {
+	width := hi - lo
+	mus := append([]float64(nil), obs...)
+	sort.Float64s(mus)
+
+	minSigma := width / math.Min(100, float64(len(mus)+1))
+	sigmas := make([]float64, len(mus))
+	for i, mu := range mus {
+		left, right := mu-lo, hi-mu
+		if i > 0 {
+			left = mu - mus[i-1]
+		}
+		if i < len(mus)-1 {
+			right = mus[i+1] - mu
+		}
+		sigmas[i] = math.Min(math.Max(math.Max(left, right), minSigma), width)
+	}
+
+	// The prior keeps the whole range reachable however tight the
+	// observations are
+	mus = append(mus, (lo+hi)/2)
+	sigmas = append(sigmas, width)
+
+	return &parzen{mus: mus, sigmas: sigmas, lo: lo, hi: hi}
+}
+
+func (p *parzen) sample(rng *rand.Rand) float64 {
+	k := rng.Intn(len(p.mus))
+
+	for try := 0; try < 100; try++ {
+		x := p.mus[k] + rng.NormFloat64()*p.sigmas[k]
+		if x >= p.lo && x <= p.hi {
+			return x
+		}
+	}
+
+	return math.Min(math.Max(p.mus[k], p.lo), p.hi)
+}
+
+func (p *parzen) logPDF(x float64) float64 {
+	sum := 0.0
+
+	for k, mu := range p.mus {
+		sigma := p.sigmas[k]
+		mass := normalCDF((p.hi-mu)/sigma) - normalCDF((p.lo-mu)/sigma)
+		z := (x - mu) / sigma
+		sum += math.Exp(-0.5*z*z) / (sigma * math.Sqrt(2*math.Pi) * math.Max(mass, 1e-12))
+	}
+
+	return math.Log(sum/float64(len(p.mus)) + 1e-300)
+}
+
+func normalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+// categoricalDensity weights each choice by its count plus one.
+type categoricalDensity struct {
+	probs []float64
+}
+
+func newCategoricalDensity(cs CategoricalSpace, name string, trials []Trial) *categoricalDensity {
+	probs := make([]float64, len(cs))
+	for k := range probs {
+		probs[k] = 1
+	}
+
+	for _, t := range trials {
+		want, _ := json.Marshal(t.Params[name])
+		for k, choice := range cs {
+			if got, _ := json.Marshal(choice); string(got) == string(want) {
+				probs[k]++
+				break
+			}
+		}
+	}
+
+	total := float64(len(trials) + len(cs))
+	for k := range probs {
+		probs[k] /= total
+	}
+
+	return &categoricalDensity{probs: probs}
+}
+
+func (c *categoricalDensity) sample(rng *rand.Rand) float64 {
+	u := rng.Float64()
+	for k, p := range c.probs {
+		if u < p {
+			return float64(k)
+		}
+		u -= p
+	}
+
+	return float64(len(c.probs) - 1)
+}
+
+func (c *categoricalDensity) logPDF(x float64) float64 {
+	return math.Log(c.probs[int(x)])
+}
+
+func meanOf(xs []float64) float64 {
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+
+	return sum / float64(len(xs))
+}
+
+var _ core.Model = (*BayesSearchCV)(nil)
+var _ core.Estimator = (*BayesSearchCV)(nil)
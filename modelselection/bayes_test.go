@@ -0,0 +1,217 @@
+package modelselection
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golearn-lite/matrix"
+	"golearn-lite/metrics"
+	"golearn-lite/regression"
+)
+
+// blobs returns n samples of three well separated 2-d classes.
+func blobs(n int) (matrix.Matrix, []float64) {
+	X := matrix.Zeros(n, 2)
+	y := make([]float64, n)
+
+	for i := 0; i < n; i++ {
+		c := float64(i % 3)
+		y[i] = c
+		X.Set(i, 0, 4*c+float64(i%5)*0.1)
+		X.Set(i, 1, -2*c+float64(i%7)*0.1)
+	}
+
+	return X, y
+}
+
+func newTestBayes(nTrials int, history string) *BayesSearchCV {
+	seed := int64(7)
+	space := map[string]Space{
+		"learning_rate": FloatSpace{Low: 1e-3, High: 1, Log: true},
+		"iterations":    IntSpace{Low: 20, High: 300},
+	}
+
+	bs := NewBayesSearchCV(regression.NewMultiClassLogisticRegression(), space, nTrials, NewStratifiedKFold(3), metrics.Accuracy)
+	bs.RandomState = &seed
+	bs.NStartup = 10
+	bs.HistoryPath = history
+
+	return bs
+}
+
+func TestBayesSearchResume(t *testing.T) {
+	X, y := blobs(60)
+	history := filepath.Join(t.TempDir(), "trials.json")
+
+	first := newTestBayes(4, history)
+	if err := first.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	resumed := newTestBayes(8, history)
+	if err := resumed.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if len(resumed.Trials) != 8 || len(resumed.Results) != 8 {
+		t.Fatalf("%d trials and %d results, want 8", len(resumed.Trials), len(resumed.Results))
+	}
+
+	for k, trial := range first.Trials {
+		got := resumed.Trials[k]
+		if got.Number != k || got.Score != trial.Score {
+			t.Errorf("trial %d not restored: %+v", k, got)
+		}
+		// Types must come back as SetParams expects them
+		if _, ok := got.Params["iterations"].(int); !ok {
+			t.Errorf("trial %d iterations restored as %T", k, got.Params["iterations"])
+		}
+	}
+
+	// Still in the startup phase: new draws must not repeat loaded ones
+	for _, old := range first.Trials {
+		for _, added := range resumed.Trials[4:] {
+			if old.Params["learning_rate"] == added.Params["learning_rate"] {
+				t.Fatalf("resumed search repeated %v", old.Params)
+			}
+		}
+	}
+
+	// A finished history is not extended
+	again := newTestBayes(8, history)
+	if err := again.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Trials) != 8 || again.BestEstimator == nil {
+		t.Errorf("%d trials, best estimator %v", len(again.Trials), again.BestEstimator)
+	}
+}
+
+func TestBayesSearchFindsGoodParams(t *testing.T) {
+	X, y := blobs(90)
+
+	bs := newTestBayes(15, "")
+	bs.NStartup = 5
+	bs.Prune = true
+	if err := bs.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	if bs.BestScore < 0.9 {
+		t.Errorf("best score %v, want at least 0.9", bs.BestScore)
+	}
+	for _, trial := range bs.Trials {
+		if trial.State == TrialFailed {
+			t.Errorf("trial %d failed: %s", trial.Number, trial.Error)
+		}
+		if trial.State == TrialComplete && len(trial.Scores) != 3 {
+			t.Errorf("complete trial %d has %d scores", trial.Number, len(trial.Scores))
+		}
+	}
+}
+
+func TestSpaceValidate(t *testing.T) {
+	bad := []Space{
+		FloatSpace{Low: 1, High: 1},
+		FloatSpace{Low: 0, High: 1, Log: true},
+		IntSpace{Low: 3, High: 2},
+		IntSpace{Low: 0, High: 5, Log: true},
+		CategoricalSpace{},
+	}
+	for _, sp := range bad {
+		if err := sp.validate(); err == nil {
+			t.Errorf("%#v accepted", sp)
+		}
+	}
+}
+
+func TestBayesSearchRejectsMismatchedHistory(t *testing.T) {
+	X, y := blobs(30)
+	history := filepath.Join(t.TempDir(), "trials.json")
+
+	if err := newTestBayes(2, history).Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	renamed := newTestBayes(4, history)
+	renamed.Space = map[string]Space{
+		"learning_rate": FloatSpace{Low: 1e-3, High: 1, Log: true},
+		"epochs":        IntSpace{Low: 20, High: 300},
+	}
+	if err := renamed.Fit(X, y); err == nil {
+		t.Error("expected an error for a parameter outside the search space")
+	}
+
+	smaller := newTestBayes(4, history)
+	delete(smaller.Space, "iterations")
+	if err := smaller.Fit(X, y); err == nil {
+		t.Error("expected an error for a history with extra parameters")
+	}
+
+	if err := os.WriteFile(history, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := newTestBayes(4, history).Fit(X, y); err == nil {
+		t.Error("expected an error for a corrupt history")
+	}
+}
+
+func TestBayesSearchTimeout(t *testing.T) {
+	X, y := blobs(30)
+
+	bs := newTestBayes(5, "")
+	bs.Timeout = time.Nanosecond
+	if err := bs.Fit(X, y); err == nil {
+		t.Error("expected an error when no trial runs before the timeout")
+	}
+
+	// Loaded trials still count once the time is up
+	history := filepath.Join(t.TempDir(), "trials.json")
+	if err := newTestBayes(2, history).Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	resumed := newTestBayes(5, history)
+	resumed.Timeout = time.Nanosecond
+	if err := resumed.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if len(resumed.Trials) != 2 || resumed.BestEstimator == nil {
+		t.Errorf("%d trials, best estimator %v", len(resumed.Trials), resumed.BestEstimator)
+	}
+}
+
+func TestBayesSearchPrunes(t *testing.T) {
+	X, y := ramp(12)
+	seed := int64(3)
+
+	bs := NewBayesSearchCV(&meanModel{}, map[string]Space{"shift": FloatSpace{Low: -6, High: 6}}, 30, NewKFold(4), metrics.MAE)
+	bs.RandomState = &seed
+	bs.LowerIsBetter = true
+	bs.NStartup = 5
+	bs.Prune = true
+	if err := bs.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	pruned := 0
+	for k, trial := range bs.Trials {
+		if trial.State != TrialPruned {
+			continue
+		}
+		pruned++
+		if len(trial.Scores) == 0 || len(trial.Scores) >= 4 {
+			t.Errorf("pruned trial %d has %d scores", trial.Number, len(trial.Scores))
+		}
+		if res := bs.Results[k]; !errors.Is(res.Err, ErrTrialPruned) || res.Rank == 1 {
+			t.Errorf("pruned trial %d result %+v", trial.Number, res)
+		}
+	}
+	if pruned == 0 {
+		t.Error("no trial was pruned")
+	}
+	if best := bs.Trials[bs.BestIndex]; best.State != TrialComplete {
+		t.Errorf("best trial is %s", best.State)
+	}
+}
@@ -0,0 +1,163 @@
+package modelselection
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"golearn-lite/core"
+	"golearn-lite/matrix"
+)
+
+// CVOptions configures CrossValidate.
+type CVOptions struct {
+	Metric       func(yTrue, yPred []float64) float64
+	Groups       []int // passed to the splitter, for GroupKFold
+	NJobs        int   // folds fitted at once; 0 means runtime.GOMAXPROCS
+	ReturnModels bool  // keep the model fitted on each fold
+}
+
+// CVResult holds one entry per fold, in the splitter's fold order. Scores
+// are the metric's values as is, so whether higher is better depends on
+// the metric.
+type CVResult struct {
+	Scores     []float64
+	FitTimes   []time.Duration
+	ScoreTimes []time.Duration
+	Models     []core.Model // nil unless CVOptions.ReturnModels
+}
+
+// Mean returns the average fold score.
+func (r *CVResult) Mean() float64 {
+	sum := 0.0
+	for _, s := range r.Scores {
+		sum += s
+	}
+
+	return sum / float64(len(r.Scores))
+}
+
+// Std returns the population standard deviation of the fold scores.
+func (r *CVResult) Std() float64 {
+	mean := r.Mean()
+	sum := 0.0
+	for _, s := range r.Scores {
+		sum += (s - mean) * (s - mean)
+	}
+
+	return math.Sqrt(sum / float64(len(r.Scores)))
+}
+
+// CrossValScore returns the metric of m on each fold of cv.
+func CrossValScore(m core.Model, X matrix.Matrix, y []float64, cv Splitter, metric func(yTrue, yPred []float64) float64) ([]float64, error) {
+	res, err := CrossValidate(m, X, y, cv, CVOptions{Metric: metric})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Scores, nil
+}
+
+// CrossValidate fits a fresh clone of m (see core.Clone) on the training
+// rows of every fold and scores it on the test rows, running up to NJobs
+// folds concurrently. m itself is never fitted. If any fold fails, the
+// error of the first failing fold is returned.
+func CrossValidate(m core.Model, X matrix.Matrix, y []float64, cv Splitter, opts CVOptions) (*CVResult, error) {
+	if opts.Metric == nil {
+		return nil, errors.New("cross-validation needs a metric")
+	}
+	if err := checkLabels(X, y); err != nil {
+		return nil, err
+	}
+
+	folds, err := cv.Split(X, y, opts.Groups)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &CVResult{
+		Scores:     make([]float64, len(folds)),
+		FitTimes:   make([]time.Duration, len(folds)),
+		ScoreTimes: make([]time.Duration, len(folds)),
+	}
+	if opts.ReturnModels {
+		res.Models = make([]core.Model, len(folds))
+	}
+
+	errs := make([]error, len(folds))
+	parallel(len(folds), opts.NJobs, func(k int) {
+		fold := folds[k]
+
+		model, err := core.Clone(m)
+		if err != nil {
+			errs[k] = err
+			return
+		}
+
+		start := time.Now()
+		if err := model.Fit(X.SelectRows(fold.Train), subset(y, fold.Train)); err != nil {
+			errs[k] = fmt.Errorf("fold %d: %w", k, err)
+			return
+		}
+		res.FitTimes[k] = time.Since(start)
+
+		start = time.Now()
+		preds, err := core.Predict(model, X.SelectRows(fold.Test))
+		if err != nil {
+			errs[k] = fmt.Errorf("fold %d: %w", k, err)
+			return
+		}
+		res.Scores[k] = opts.Metric(subset(y, fold.Test), preds)
+		res.ScoreTimes[k] = time.Since(start)
+
+		if opts.ReturnModels {
+			res.Models[k] = model
+		}
+	})
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// parallel calls fn for every task in [0, n) on at most jobs goroutines;
+// jobs <= 0 means runtime.GOMAXPROCS.
+func parallel(n, jobs int, fn func(task int)) {
+	if jobs <= 0 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+
+	tasks := make(chan int, n)
+	for k := 0; k < n; k++ {
+		tasks <- k
+	}
+	close(tasks)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(jobs, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range tasks {
+				fn(k)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func subset(y []float64, idx []int) []float64 {
+	out := make([]float64, len(idx))
+	for k, i := range idx {
+		out[k] = y[i]
+	}
+
+	return out
+}
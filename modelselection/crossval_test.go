@@ -0,0 +1,136 @@
+package modelselection
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"golearn-lite/matrix"
+	"golearn-lite/metrics"
+	"golearn-lite/neighbors"
+)
+
+// meanModel predicts the mean training label plus Shift, and fails to fit
+// when Fail is set, so fold and candidate scores are known exactly.
+type meanModel struct {
+	Shift float64
+	Fail  bool
+	mean  float64
+}
+
+func (m *meanModel) Fit(X matrix.Matrix, y []float64) error {
+	if m.Fail {
+		return errors.New("fit failed")
+	}
+
+	m.mean = 0
+	for _, v := range y {
+		m.mean += v / float64(len(y))
+	}
+
+	return nil
+}
+
+func (m *meanModel) Predict(X matrix.Matrix) []float64 {
+	preds := make([]float64, X.Rows)
+	for i := range preds {
+		preds[i] = m.mean + m.Shift
+	}
+
+	return preds
+}
+
+func (m *meanModel) GetParams() map[string]interface{} {
+	return map[string]interface{}{"shift": m.Shift, "fail": m.Fail}
+}
+
+func (m *meanModel) SetParams(params map[string]interface{}) error {
+	if v, ok := params["shift"].(float64); ok {
+		m.Shift = v
+	}
+	if v, ok := params["fail"].(bool); ok {
+		m.Fail = v
+	}
+
+	return nil
+}
+
+// ramp returns n rows with y[i] = i.
+func ramp(n int) (matrix.Matrix, []float64) {
+	y := make([]float64, n)
+	for i := range y {
+		y[i] = float64(i)
+	}
+
+	return matrix.Zeros(n, 1), y
+}
+
+func TestCrossValScore(t *testing.T) {
+	X, y := ramp(6)
+
+	// Fold k tests rows 2k and 2k+1 against the mean of the other four
+	scores, err := CrossValScore(&meanModel{}, X, y, NewKFold(3), metrics.MAE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{3, 0.5, 3}; !reflect.DeepEqual(scores, want) {
+		t.Errorf("scores %v, want %v", scores, want)
+	}
+
+	res := CVResult{Scores: scores}
+	if math.Abs(res.Mean()-6.5/3) > 1e-12 || math.Abs(res.Std()-math.Sqrt(50)/6) > 1e-12 {
+		t.Errorf("mean %v, std %v", res.Mean(), res.Std())
+	}
+}
+
+func TestCrossValidate(t *testing.T) {
+	X, y := blobs(30)
+	knn := neighbors.NewKNN(3, "classification")
+
+	for _, jobs := range []int{1, 4} {
+		res, err := CrossValidate(knn, X, y, NewStratifiedKFold(5), CVOptions{Metric: metrics.Accuracy, NJobs: jobs, ReturnModels: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Scores) != 5 || len(res.FitTimes) != 5 || len(res.ScoreTimes) != 5 || len(res.Models) != 5 {
+			t.Fatalf("NJobs %d: result lengths %d/%d/%d/%d, want 5", jobs, len(res.Scores), len(res.FitTimes), len(res.ScoreTimes), len(res.Models))
+		}
+		if res.Mean() != 1 || res.Std() != 0 {
+			t.Errorf("NJobs %d: scores %v, want all 1", jobs, res.Scores)
+		}
+		for k, m := range res.Models {
+			if m == knn || !m.(*neighbors.KNN).IsFitted() {
+				t.Errorf("NJobs %d: model %d is not a fitted clone", jobs, k)
+			}
+		}
+	}
+	if knn.IsFitted() {
+		t.Error("CrossValidate fitted the model it was given")
+	}
+
+	res, err := CrossValidate(knn, X, y, NewKFold(3), CVOptions{Metric: metrics.Accuracy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Models != nil {
+		t.Error("Models kept without ReturnModels")
+	}
+}
+
+func TestCrossValidateErrors(t *testing.T) {
+	X, y := ramp(6)
+
+	if _, err := CrossValidate(&meanModel{}, X, y, NewKFold(3), CVOptions{}); err == nil {
+		t.Error("expected an error without a metric")
+	}
+	if _, err := CrossValidate(&meanModel{}, X, y[:4], NewKFold(3), CVOptions{Metric: metrics.MAE}); err == nil {
+		t.Error("expected an error for mismatched X and y")
+	}
+	if _, err := CrossValidate(&meanModel{}, X, y, NewGroupKFold(3), CVOptions{Metric: metrics.MAE}); err == nil {
+		t.Error("expected the splitter's error for missing groups")
+	}
+	if _, err := CrossValidate(&meanModel{Fail: true}, X, y, NewKFold(3), CVOptions{Metric: metrics.MAE}); err == nil || err.Error() != "fold 0: fit failed" {
+		t.Errorf("err = %v, want the first fold's fit error", err)
+	}
+}
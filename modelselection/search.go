@@ -0,0 +1,505 @@
+package modelselection
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"golearn-lite/core"
+	"golearn-lite/matrix"
+	"golearn-lite/validation"
+)
+
+// ParamGrid maps parameter names, as used by core.Params, to the values to
+// try. GridSearchCV evaluates every combination.
+type ParamGrid map[string][]interface{}
+
+// Distribution draws candidate values for RandomizedSearchCV. A
+// distribution that also has a Validate() error method is checked before
+// the search samples from it, as the built-in ones are.
+type Distribution interface {
+	Sample(rng *rand.Rand) interface{}
+}
+
+// Uniform draws a float64 uniformly from [Low, High). It requires finite
+// bounds with Low < High.
+type Uniform struct {
+	Low, High float64
+}
+
+// LogUniform draws a float64 whose logarithm is uniform, for scale
+// parameters such as learning rates. It requires 0 < Low < High.
+type LogUniform struct {
+	Low, High float64
+}
+
+// IntRange draws an int uniformly from [Low, High], both ends included.
+// It requires Low <= High.
+type IntRange struct {
+	Low, High int
+}
+
+// Choice draws one of its values with equal probability. It must not be
+// empty.
+type Choice []interface{}
+
+func (d Uniform) Sample(rng *rand.Rand) interface{} {
+	return d.Low + rng.Float64()*(d.High-d.Low)
+}
+
+func (d LogUniform) Sample(rng *rand.Rand) interface{} {
+	lo, hi := math.Log(d.Low), math.Log(d.High)
+	return math.Exp(lo + rng.Float64()*(hi-lo))
+}
+
+func (d IntRange) Sample(rng *rand.Rand) interface{} {
+	return d.Low + rng.Intn(d.High-d.Low+1)
+}
+
+func (d Choice) Sample(rng *rand.Rand) interface{} {
+	return d[rng.Intn(len(d))]
+}
+
+func (d Uniform) Validate() error {
+	if math.IsInf(d.Low, 0) || math.IsInf(d.High, 0) || !(d.Low < d.High) {
+		return fmt.Errorf("uniform range [%v, %v) is empty or unbounded", d.Low, d.High)
+	}
+
+	return nil
+}
+
+func (d LogUniform) Validate() error {
+	if math.IsInf(d.High, 0) || !(d.Low > 0 && d.Low < d.High) {
+		return fmt.Errorf("log-uniform range [%v, %v) needs 0 < Low < High", d.Low, d.High)
+	}
+
+	return nil
+}
+
+func (d IntRange) Validate() error {
+	if d.Low > d.High {
+		return fmt.Errorf("int range [%d, %d] is empty", d.Low, d.High)
+	}
+
+	return nil
+}
+
+func (d Choice) Validate() error {
+	if len(d) == 0 {
+		return errors.New("choice has no values")
+	}
+
+	return nil
+}
+
+// CandidateResult is one row of a search's results table. A candidate
+// whose parameters could not be set, or whose cross-validation failed,
+// has Err set, NaN scores and the worst rank.
+type CandidateResult struct {
+	Params        map[string]interface{}
+	Scores        []float64
+	MeanScore     float64
+	StdScore      float64
+	MeanFitTime   time.Duration
+	MeanScoreTime time.Duration
+	Rank          int
+	Err           error
+}
+
+// SearchCV holds the configuration and results shared by GridSearchCV and
+// RandomizedSearchCV. After Fit it predicts with BestEstimator, so a search
+// can stand in for the model it tuned.
+type SearchCV struct {
+	Estimator     core.Model
+	CV            Splitter
+	Metric        func(yTrue, yPred []float64) float64
+	LowerIsBetter bool  // set for losses such as MSE; by default higher scores win
+	Groups        []int // passed to the splitter, for GroupKFold
+	NJobs         int   // candidates evaluated at once; 0 means runtime.GOMAXPROCS
+	Refit         bool  // fit BestEstimator on all of X with BestParams
+
+	Results       []CandidateResult
+	BestIndex     int
+	BestParams    map[string]interface{}
+	BestScore     float64
+	BestEstimator core.Model
+}
+
+// GridSearchCV cross-validates every combination in ParamGrid.
+type GridSearchCV struct {
+	SearchCV
+	ParamGrid ParamGrid
+}
+
+// RandomizedSearchCV cross-validates NIter parameter sets drawn from
+// Distributions using RandomState.
+type RandomizedSearchCV struct {
+	SearchCV
+	Distributions map[string]Distribution
+	NIter         int
+	RandomState   *int64
+}
+
+func NewGridSearchCV(estimator core.Model, grid ParamGrid, cv Splitter, metric func(yTrue, yPred []float64) float64) *GridSearchCV {
+	return &GridSearchCV{
+		SearchCV:  SearchCV{Estimator: estimator, CV: cv, Metric: metric, Refit: true},
+		ParamGrid: grid,
+	}
+}
+
+func NewRandomizedSearchCV(estimator core.Model, distributions map[string]Distribution, nIter int, cv Splitter, metric func(yTrue, yPred []float64) float64) *RandomizedSearchCV {
+	return &RandomizedSearchCV{
+		SearchCV:      SearchCV{Estimator: estimator, CV: cv, Metric: metric, Refit: true},
+		Distributions: distributions,
+		NIter:         nIter,
+	}
+}
+
+func (gs *GridSearchCV) Fit(X matrix.Matrix, y []float64) error {
+	candidates, err := expandGrid(gs.ParamGrid)
+	if err != nil {
+		return err
+	}
+
+	return gs.run(candidates, X, y)
+}
+
+func (rs *RandomizedSearchCV) Fit(X matrix.Matrix, y []float64) error {
+	if rs.NIter < 1 {
+		return errors.New("NIter must be at least 1")
+	}
+
+	names := make([]string, 0, len(rs.Distributions))
+	for name, d := range rs.Distributions {
+		if d == nil {
+			return fmt.Errorf("parameter %q has no distribution", name)
+		}
+		if v, ok := d.(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return fmt.Errorf("parameter %q: %w", name, err)
+			}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rng := core.RandFromState(rs.RandomState)
+	candidates := make([]map[string]interface{}, rs.NIter)
+	for c := range candidates {
+		candidates[c] = make(map[string]interface{}, len(names))
+		for _, name := range names {
+			candidates[c][name] = rs.Distributions[name].Sample(rng)
+		}
+	}
+
+	return rs.run(candidates, X, y)
+}
+
+// expandGrid lists every combination of grid values, varying the
+// alphabetically last parameter fastest.
+func expandGrid(grid ParamGrid) ([]map[string]interface{}, error) {
+	names := make([]string, 0, len(grid))
+	for name, values := range grid {
+		if len(values) == 0 {
+			return nil, fmt.Errorf("parameter %q has no values", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	candidates := []map[string]interface{}{{}}
+	for _, name := range names {
+		next := make([]map[string]interface{}, 0, len(candidates)*len(grid[name]))
+		for _, base := range candidates {
+			for _, v := range grid[name] {
+				c := make(map[string]interface{}, len(base)+1)
+				for k, bv := range base {
+					c[k] = bv
+				}
+				c[name] = v
+				next = append(next, c)
+			}
+		}
+		candidates = next
+	}
+
+	return candidates, nil
+}
+
+// run cross-validates every candidate, ranks them and refits the best.
+func (s *SearchCV) run(candidates []map[string]interface{}, X matrix.Matrix, y []float64) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	s.Results = make([]CandidateResult, len(candidates))
+
+	parallel(len(candidates), s.NJobs, func(c int) {
+		s.Results[c] = s.evaluate(candidates[c], X, y)
+	})
+
+	return s.finish(X, y)
+}
+
+func (s *SearchCV) validate() error {
+	if s.Estimator == nil || s.CV == nil || s.Metric == nil {
+		return errors.New("search needs an estimator, a splitter and a metric")
+	}
+
+	return nil
+}
+
+// finish ranks Results, records the best candidate and, with Refit, fits
+// BestEstimator on all of X.
+func (s *SearchCV) finish(X matrix.Matrix, y []float64) error {
+	s.BestParams, s.BestEstimator = nil, nil
+
+	s.rank()
+	best := s.Results[s.BestIndex]
+	if !best.valid() {
+		return fmt.Errorf("no candidate produced a valid score: %w", errors.Join(candidateErrors(s.Results)...))
+	}
+	s.BestParams = best.Params
+	s.BestScore = best.MeanScore
+
+	if !s.Refit {
+		return nil
+	}
+
+	model, err := withParams(s.Estimator, s.BestParams)
+	if err != nil {
+		return err
+	}
+	if err := model.Fit(X, y); err != nil {
+		return fmt.Errorf("refit: %w", err)
+	}
+	s.BestEstimator = model
+
+	return nil
+}
+
+func (s *SearchCV) evaluate(params map[string]interface{}, X matrix.Matrix, y []float64) CandidateResult {
+	res := CandidateResult{Params: params, MeanScore: math.NaN(), StdScore: math.NaN()}
+
+	model, err := withParams(s.Estimator, params)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	// Candidates already run concurrently, so folds run one at a time
+	cv, err := CrossValidate(model, X, y, s.CV, CVOptions{Metric: s.Metric, Groups: s.Groups, NJobs: 1})
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	res.Scores = cv.Scores
+	res.MeanScore, res.StdScore = cv.Mean(), cv.Std()
+	res.MeanFitTime = meanDuration(cv.FitTimes)
+	res.MeanScoreTime = meanDuration(cv.ScoreTimes)
+
+	return res
+}
+
+// rank assigns rank 1 to the best mean score, sharing ranks between ties,
+// and records the first best candidate in BestIndex.
+func (s *SearchCV) rank() {
+	order := make([]int, len(s.Results))
+	for c := range order {
+		order[c] = c
+	}
+
+	better := func(a, b CandidateResult) bool {
+		if !a.valid() || !b.valid() {
+			return a.valid() && !b.valid()
+		}
+		if s.LowerIsBetter {
+			return a.MeanScore < b.MeanScore
+		}
+		return a.MeanScore > b.MeanScore
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return better(s.Results[order[a]], s.Results[order[b]])
+	})
+
+	for pos, c := range order {
+		s.Results[c].Rank = pos + 1
+		if pos > 0 && !better(s.Results[order[pos-1]], s.Results[c]) {
+			s.Results[c].Rank = s.Results[order[pos-1]].Rank
+		}
+	}
+	s.BestIndex = order[0]
+}
+
+func (r CandidateResult) valid() bool {
+	return r.Err == nil && !math.IsNaN(r.MeanScore)
+}
+
+func candidateErrors(results []CandidateResult) []error {
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, r.Err)
+		}
+	}
+	if len(errs) == 0 {
+		errs = append(errs, errors.New("metric returned NaN"))
+	}
+
+	return errs
+}
+
+// withParams returns an unfitted clone of m with params applied. Unknown
+// names and values whose type differs from the current value are errors,
+// since SetParams silently ignores both.
+func withParams(m core.Model, params map[string]interface{}) (core.Model, error) {
+	model, err := core.Clone(m)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := model.(core.Params)
+	if !ok {
+		return nil, fmt.Errorf("%T does not implement core.Params", model)
+	}
+
+	current := p.GetParams()
+	for name, v := range params {
+		old, ok := current[name]
+		if !ok {
+			return nil, fmt.Errorf("%T has no parameter %q", model, name)
+		}
+		if reflect.TypeOf(old) != reflect.TypeOf(v) {
+			return nil, fmt.Errorf("parameter %q must be %T, got %T", name, old, v)
+		}
+	}
+
+	if err := p.SetParams(params); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func meanDuration(ds []time.Duration) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+
+	var sum time.Duration
+	for _, d := range ds {
+		sum += d
+	}
+
+	return sum / time.Duration(len(ds))
+}
+
+func (s *SearchCV) Predict(X matrix.Matrix) []float64 {
+	preds, _ := s.PredictE(X)
+	return preds
+}
+
+func (s *SearchCV) PredictE(X matrix.Matrix) ([]float64, error) {
+	if err := validation.CheckFitted(s.IsFitted()); err != nil {
+		return nil, err
+	}
+
+	return core.Predict(s.BestEstimator, X)
+}
+
+// IsFitted reports whether a refitted BestEstimator is available.
+func (s *SearchCV) IsFitted() bool {
+	return s.BestEstimator != nil
+}
+
+func (s *SearchCV) Score(X matrix.Matrix, y []float64, metric func(yTrue, yPred []float64) float64) float64 {
+	return core.Score(s.PredictE, X, y, metric)
+}
+
+// WriteCSV writes the results table with one row per candidate, in
+// evaluation order: rank, mean and std score, mean fit and score seconds,
+// the score of every split, one param_<name> column per parameter and the
+// error, if any.
+func (s *SearchCV) WriteCSV(w io.Writer) error {
+	names := map[string]bool{}
+	nSplits := 0
+	for _, r := range s.Results {
+		for name := range r.Params {
+			names[name] = true
+		}
+		nSplits = max(nSplits, len(r.Scores))
+	}
+	paramNames := make([]string, 0, len(names))
+	for name := range names {
+		paramNames = append(paramNames, name)
+	}
+	sort.Strings(paramNames)
+
+	header := []string{"rank", "mean_score", "std_score", "mean_fit_time", "mean_score_time"}
+	for k := 0; k < nSplits; k++ {
+		header = append(header, fmt.Sprintf("split%d_score", k))
+	}
+	for _, name := range paramNames {
+		header = append(header, "param_"+name)
+	}
+	header = append(header, "error")
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, r := range s.Results {
+		row := []string{
+			strconv.Itoa(r.Rank),
+			formatFloat(r.MeanScore),
+			formatFloat(r.StdScore),
+			formatFloat(r.MeanFitTime.Seconds()),
+			formatFloat(r.MeanScoreTime.Seconds()),
+		}
+		for k := 0; k < nSplits; k++ {
+			if k < len(r.Scores) {
+				row = append(row, formatFloat(r.Scores[k]))
+			} else {
+				row = append(row, "")
+			}
+		}
+		for _, name := range paramNames {
+			if v, ok := r.Params[name]; ok {
+				row = append(row, fmt.Sprint(v))
+			} else {
+				row = append(row, "")
+			}
+		}
+		if r.Err != nil {
+			row = append(row, r.Err.Error())
+		} else {
+			row = append(row, "")
+		}
+
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var _ core.Model = (*GridSearchCV)(nil)
+var _ core.Model = (*RandomizedSearchCV)(nil)
+var _ core.Estimator = (*GridSearchCV)(nil)
+var _ core.Estimator = (*RandomizedSearchCV)(nil)
+var _ core.Scorable = (*GridSearchCV)(nil)
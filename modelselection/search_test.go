@@ -0,0 +1,179 @@
+package modelselection
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"reflect"
+	"testing"
+
+	"golearn-lite/metrics"
+	"golearn-lite/neighbors"
+)
+
+func TestRandomizedSearchRejectsBadDistributions(t *testing.T) {
+	X, y := blobs(30)
+
+	bad := map[string]Distribution{
+		"empty int range":    IntRange{Low: 5, High: 4},
+		"empty choice":       Choice{},
+		"log-uniform from 0": LogUniform{Low: 0, High: 1},
+		"empty uniform":      Uniform{Low: 1, High: 1},
+		"nil":                nil,
+	}
+	for name, d := range bad {
+		rs := NewRandomizedSearchCV(neighbors.NewKNN(1, "classification"), map[string]Distribution{"k": d}, 3, NewKFold(3), metrics.Accuracy)
+		if err := rs.Fit(X, y); err == nil {
+			t.Errorf("%s: Fit succeeded", name)
+		}
+	}
+}
+
+func TestExpandGrid(t *testing.T) {
+	candidates, err := expandGrid(ParamGrid{"b": {1, 2, 3}, "a": {"x", "y"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 6 {
+		t.Fatalf("%d candidates, want 6", len(candidates))
+	}
+	// The alphabetically last parameter varies fastest
+	if candidates[0]["a"] != "x" || candidates[0]["b"] != 1 || candidates[1]["b"] != 2 || candidates[3]["a"] != "y" {
+		t.Errorf("candidates in unexpected order: %v", candidates)
+	}
+
+	if _, err := expandGrid(ParamGrid{"a": {}}); err == nil {
+		t.Error("expected an error for a parameter without values")
+	}
+}
+
+func TestGridSearchPicksBestK(t *testing.T) {
+	X, y := blobs(60)
+	// Relabel a few points so a single neighbour overfits them
+	for _, i := range []int{3, 17, 31, 44} {
+		y[i] = float64((int(y[i]) + 1) % 3)
+	}
+
+	gs := NewGridSearchCV(neighbors.NewKNN(1, "classification"), ParamGrid{"k": {1, 7}}, NewStratifiedKFold(3), metrics.Accuracy)
+	if err := gs.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	if gs.BestParams["k"] != 7 || gs.Results[gs.BestIndex].Rank != 1 || gs.BestScore != gs.Results[1].MeanScore {
+		t.Fatalf("best %v with score %v, results %+v", gs.BestParams, gs.BestScore, gs.Results)
+	}
+	best, ok := gs.BestEstimator.(*neighbors.KNN)
+	if !ok || best.K != 7 || !best.IsFitted() {
+		t.Fatalf("BestEstimator = %+v, want a fitted KNN with k 7", gs.BestEstimator)
+	}
+	if gs.Estimator.(*neighbors.KNN).IsFitted() {
+		t.Error("the search fitted its template estimator")
+	}
+
+	want, err := best.PredictE(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := gs.PredictE(X)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("search predictions differ from BestEstimator's: %v", err)
+	}
+}
+
+func TestSearchRanksTiesAndFailures(t *testing.T) {
+	X, y := ramp(6)
+
+	// Mean MAEs are 13/6, 14/6, 14/6 and 24/6, so shifts 1 and -1 share
+	// rank 2 and the next rank is 4
+	grid := ParamGrid{"shift": {0.0, 1.0, -1.0, 4.0}}
+	gs := NewGridSearchCV(&meanModel{}, grid, NewKFold(3), metrics.MAE)
+	gs.LowerIsBetter = true
+	gs.Refit = false
+	if err := gs.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	ranks := make([]int, len(gs.Results))
+	for c, r := range gs.Results {
+		ranks[c] = r.Rank
+	}
+	if want := []int{1, 2, 2, 4}; !reflect.DeepEqual(ranks, want) {
+		t.Errorf("ranks %v, want %v; results %+v", ranks, want, gs.Results)
+	}
+	if gs.BestIndex != 0 || gs.BestEstimator != nil || gs.IsFitted() {
+		t.Errorf("BestIndex %d, BestEstimator %v without Refit", gs.BestIndex, gs.BestEstimator)
+	}
+
+	gs.ParamGrid = ParamGrid{"fail": {true, false}}
+	if err := gs.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	failed := gs.Results[0]
+	if failed.Err == nil || !math.IsNaN(failed.MeanScore) || failed.Rank != 2 || gs.BestIndex != 1 {
+		t.Errorf("failed candidate %+v, BestIndex %d", failed, gs.BestIndex)
+	}
+
+	gs.ParamGrid = ParamGrid{"fail": {true}}
+	if err := gs.Fit(X, y); err == nil {
+		t.Error("expected an error when every candidate fails")
+	}
+	gs.ParamGrid = ParamGrid{"shift": {1, 0.0}}
+	if err := gs.Fit(X, y); err != nil || gs.Results[0].Err == nil {
+		t.Errorf("an int for a float64 parameter should fail the candidate, got %v, %+v", err, gs.Results)
+	}
+}
+
+func TestSearchWriteCSV(t *testing.T) {
+	X, y := ramp(6)
+
+	gs := NewGridSearchCV(&meanModel{}, ParamGrid{"shift": {0.0, 2.0}, "fail": {false, true}}, NewKFold(3), metrics.MAE)
+	gs.LowerIsBetter = true
+	if err := gs.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := gs.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := []string{"rank", "mean_score", "std_score", "mean_fit_time", "mean_score_time", "split0_score", "split1_score", "split2_score", "param_fail", "param_shift", "error"}
+	if !reflect.DeepEqual(rows[0], header) {
+		t.Fatalf("header %v, want %v", rows[0], header)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("%d rows, want a header and 4 candidates", len(rows))
+	}
+	for _, row := range rows[1:] {
+		if failed := row[8] == "true"; failed != (row[10] != "") || failed != (row[1] == "NaN") {
+			t.Errorf("row %v: error and score disagree with param_fail", row)
+		}
+	}
+}
+
+func TestRandomizedSearchIsSeeded(t *testing.T) {
+	X, y := ramp(6)
+	seed := int64(11)
+
+	run := func() []CandidateResult {
+		rs := NewRandomizedSearchCV(&meanModel{}, map[string]Distribution{"shift": Uniform{Low: -2, High: 2}}, 5, NewKFold(3), metrics.MAE)
+		rs.RandomState = &seed
+		rs.LowerIsBetter = true
+		if err := rs.Fit(X, y); err != nil {
+			t.Fatal(err)
+		}
+		return rs.Results
+	}
+
+	a, b := run(), run()
+	for c := range a {
+		shift := a[c].Params["shift"].(float64)
+		if shift < -2 || shift >= 2 || shift != b[c].Params["shift"] {
+			t.Fatalf("candidate %d: shifts %v and %v", c, shift, b[c].Params["shift"])
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package modelselection
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"golearn-lite/core"
+	"golearn-lite/matrix"
+	"golearn-lite/validation"
+)
+
+// Fold holds the row indices of one train/test split, each in ascending
+// order.
+type Fold struct {
+	Train []int
+	Test  []int
+}
+
+// Splitter partitions the rows of X into cross-validation folds. y is used
+// by splitters that stratify and groups by those that keep groups
+// together; the others ignore them and accept nil.
+type Splitter interface {
+	Split(X matrix.Matrix, y []float64, groups []int) ([]Fold, error)
+}
+
+// KFold splits the rows into NSplits consecutive folds, the first n %
+// NSplits of them one row larger. With Shuffle the rows are permuted
+// first, using RandomState.
+type KFold struct {
+	NSplits     int
+	Shuffle     bool
+	RandomState *int64
+}
+
+// StratifiedKFold is KFold with each class's rows dealt across the folds in
+// turn, so every fold keeps close to the overall class proportions.
+type StratifiedKFold struct {
+	NSplits     int
+	Shuffle     bool
+	RandomState *int64
+}
+
+// GroupKFold keeps every group in a single test fold, assigning the
+// largest groups first to the fold with the fewest rows so far.
+type GroupKFold struct {
+	NSplits int
+}
+
+// RepeatedKFold runs a shuffled KFold NRepeats times, each with a
+// different permutation drawn from RandomState.
+type RepeatedKFold struct {
+	NSplits     int
+	NRepeats    int
+	RandomState *int64
+}
+
+// LeaveOneOut tests on each row in turn and trains on all the others.
+type LeaveOneOut struct{}
+
+// TimeSeriesSplit makes NSplits expanding-window folds in which every test
+// fold follows its training rows in time, so rows must be in time order.
+// Test folds have n / (NSplits+1) rows; Gap rows between train and test
+// are skipped, and MaxTrainSize > 0 keeps only the latest training rows.
+type TimeSeriesSplit struct {
+	NSplits      int
+	MaxTrainSize int
+	Gap          int
+}
+
+func NewKFold(nSplits int) *KFold {
+	return &KFold{NSplits: nSplits}
+}
+
+func NewStratifiedKFold(nSplits int) *StratifiedKFold {
+	return &StratifiedKFold{NSplits: nSplits}
+}
+
+func NewGroupKFold(nSplits int) *GroupKFold {
+	return &GroupKFold{NSplits: nSplits}
+}
+
+func NewRepeatedKFold(nSplits, nRepeats int) *RepeatedKFold {
+	return &RepeatedKFold{NSplits: nSplits, NRepeats: nRepeats}
+}
+
+func NewTimeSeriesSplit(nSplits int) *TimeSeriesSplit {
+	return &TimeSeriesSplit{NSplits: nSplits}
+}
+
+func (kf *KFold) Split(X matrix.Matrix, y []float64, groups []int) ([]Fold, error) {
+	if err := checkNSplits(kf.NSplits, X.Rows); err != nil {
+		return nil, err
+	}
+
+	order := make([]int, X.Rows)
+	for i := range order {
+		order[i] = i
+	}
+	if kf.Shuffle {
+		rng := core.RandFromState(kf.RandomState)
+		rng.Shuffle(len(order), func(a, b int) { order[a], order[b] = order[b], order[a] })
+	}
+
+	assign := make([]int, X.Rows)
+	start := 0
+	for k := 0; k < kf.NSplits; k++ {
+		size := X.Rows / kf.NSplits
+		if k < X.Rows%kf.NSplits {
+			size++
+		}
+		for _, i := range order[start : start+size] {
+			assign[i] = k
+		}
+		start += size
+	}
+
+	return foldsFromAssignment(assign, kf.NSplits), nil
+}
+
+func (skf *StratifiedKFold) Split(X matrix.Matrix, y []float64, groups []int) ([]Fold, error) {
+	if err := checkLabels(X, y); err != nil {
+		return nil, err
+	}
+	if err := checkNSplits(skf.NSplits, X.Rows); err != nil {
+		return nil, err
+	}
+
+	byClass := make(map[float64][]int)
+	for i, label := range y {
+		byClass[label] = append(byClass[label], i)
+	}
+
+	rng := core.RandFromState(skf.RandomState)
+	assign := make([]int, X.Rows)
+	next := 0
+
+	// Deal each class round-robin, carrying the fold pointer over between
+	// classes so fold sizes differ by at most one
+	for _, class := range core.UniqueClasses(y) {
+		idx := byClass[class]
+		if skf.Shuffle {
+			rng.Shuffle(len(idx), func(a, b int) { idx[a], idx[b] = idx[b], idx[a] })
+		}
+		for _, i := range idx {
+			assign[i] = next
+			next = (next + 1) % skf.NSplits
+		}
+	}
+
+	return foldsFromAssignment(assign, skf.NSplits), nil
+}
+
+func (gkf *GroupKFold) Split(X matrix.Matrix, y []float64, groups []int) ([]Fold, error) {
+	if len(groups) != X.Rows {
+		return nil, fmt.Errorf("%w: %d groups for %d samples", validation.ErrShapeMismatch, len(groups), X.Rows)
+	}
+
+	sizes := make(map[int]int)
+	for _, g := range groups {
+		sizes[g]++
+	}
+	if err := checkNSplits(gkf.NSplits, len(sizes)); err != nil {
+		return nil, fmt.Errorf("groups: %w", err)
+	}
+
+	ids := make([]int, 0, len(sizes))
+	for g := range sizes {
+		ids = append(ids, g)
+	}
+	sort.Slice(ids, func(a, b int) bool {
+		if sizes[ids[a]] != sizes[ids[b]] {
+			return sizes[ids[a]] > sizes[ids[b]]
+		}
+		return ids[a] < ids[b]
+	})
+
+	foldOf := make(map[int]int, len(ids))
+	load := make([]int, gkf.NSplits)
+	for _, g := range ids {
+		lightest := 0
+		for k := range load {
+			if load[k] < load[lightest] {
+				lightest = k
+			}
+		}
+		foldOf[g] = lightest
+		load[lightest] += sizes[g]
+	}
+
+	assign := make([]int, X.Rows)
+	for i, g := range groups {
+		assign[i] = foldOf[g]
+	}
+
+	return foldsFromAssignment(assign, gkf.NSplits), nil
+}
+
+func (rkf *RepeatedKFold) Split(X matrix.Matrix, y []float64, groups []int) ([]Fold, error) {
+	if rkf.NRepeats < 1 {
+		return nil, errors.New("NRepeats must be at least 1")
+	}
+
+	rng := core.RandFromState(rkf.RandomState)
+	var folds []Fold
+
+	for r := 0; r < rkf.NRepeats; r++ {
+		seed := rng.Int63()
+		kf := &KFold{NSplits: rkf.NSplits, Shuffle: true, RandomState: &seed}
+
+		repeat, err := kf.Split(X, y, groups)
+		if err != nil {
+			return nil, err
+		}
+		folds = append(folds, repeat...)
+	}
+
+	return folds, nil
+}
+
+func (LeaveOneOut) Split(X matrix.Matrix, y []float64, groups []int) ([]Fold, error) {
+	if err := checkNSplits(X.Rows, X.Rows); err != nil {
+		return nil, err
+	}
+
+	assign := make([]int, X.Rows)
+	for i := range assign {
+		assign[i] = i
+	}
+
+	return foldsFromAssignment(assign, X.Rows), nil
+}
+
+func (ts *TimeSeriesSplit) Split(X matrix.Matrix, y []float64, groups []int) ([]Fold, error) {
+	if ts.NSplits < 1 {
+		return nil, errors.New("NSplits must be at least 1")
+	}
+	if ts.Gap < 0 {
+		return nil, errors.New("Gap must not be negative")
+	}
+
+	n := X.Rows
+	testSize := n / (ts.NSplits + 1)
+	firstTest := n - ts.NSplits*testSize
+	if testSize == 0 || firstTest-ts.Gap < 1 {
+		return nil, fmt.Errorf("%w: %d samples are too few for %d splits with gap %d", validation.ErrTooFewSamples, n, ts.NSplits, ts.Gap)
+	}
+
+	folds := make([]Fold, ts.NSplits)
+	for k := range folds {
+		testStart := firstTest + k*testSize
+		trainEnd := testStart - ts.Gap
+		trainStart := 0
+		if ts.MaxTrainSize > 0 {
+			trainStart = max(0, trainEnd-ts.MaxTrainSize)
+		}
+
+		folds[k] = Fold{Train: span(trainStart, trainEnd), Test: span(testStart, testStart+testSize)}
+	}
+
+	return folds, nil
+}
+
+// foldsFromAssignment builds one fold per test fold number in assign.
+func foldsFromAssignment(assign []int, nFolds int) []Fold {
+	folds := make([]Fold, nFolds)
+
+	for i, k := range assign {
+		for f := range folds {
+			if f == k {
+				folds[f].Test = append(folds[f].Test, i)
+			} else {
+				folds[f].Train = append(folds[f].Train, i)
+			}
+		}
+	}
+
+	return folds
+}
+
+func span(start, end int) []int {
+	out := make([]int, end-start)
+	for k := range out {
+		out[k] = start + k
+	}
+
+	return out
+}
+
+func checkNSplits(nSplits, n int) error {
+	if nSplits < 2 {
+		return errors.New("NSplits must be at least 2")
+	}
+	if n < nSplits {
+		return fmt.Errorf("%w: cannot make %d splits from %d", validation.ErrTooFewSamples, nSplits, n)
+	}
+
+	return nil
+}
+
+func checkLabels(X matrix.Matrix, y []float64) error {
+	if len(y) != X.Rows {
+		return fmt.Errorf("%w: X has %d samples but y has %d", validation.ErrShapeMismatch, X.Rows, len(y))
+	}
+
+	return nil
+}
+
+var _ Splitter = (*KFold)(nil)
+var _ Splitter = (*StratifiedKFold)(nil)
+var _ Splitter = (*GroupKFold)(nil)
+var _ Splitter = (*RepeatedKFold)(nil)
+var _ Splitter = LeaveOneOut{}
+var _ Splitter = (*TimeSeriesSplit)(nil)
@@ -0,0 +1,252 @@
+package modelselection
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"golearn-lite/matrix"
+	"golearn-lite/validation"
+)
+
+// checkFolds fails unless every fold's train and test rows are disjoint,
+// sorted and together cover all n rows, and the test folds cover each row
+// exactly once.
+func checkFolds(t *testing.T, folds []Fold, n int) {
+	t.Helper()
+
+	tested := make([]int, n)
+	for k, fold := range folds {
+		in := make([]int, n)
+		for _, part := range [][]int{fold.Train, fold.Test} {
+			for p, i := range part {
+				if p > 0 && part[p-1] >= i {
+					t.Fatalf("fold %d: indices not ascending: %v", k, part)
+				}
+				in[i]++
+			}
+		}
+		for i, c := range in {
+			if c != 1 {
+				t.Fatalf("fold %d: row %d is in %d of train and test", k, i, c)
+			}
+		}
+		for _, i := range fold.Test {
+			tested[i]++
+		}
+	}
+
+	for i, c := range tested {
+		if c != 1 {
+			t.Fatalf("row %d is tested %d times", i, c)
+		}
+	}
+}
+
+func testSizes(folds []Fold) []int {
+	sizes := make([]int, len(folds))
+	for k, fold := range folds {
+		sizes[k] = len(fold.Test)
+	}
+
+	return sizes
+}
+
+func TestKFold(t *testing.T) {
+	X := matrix.Zeros(11, 1)
+
+	folds, err := NewKFold(3).Split(X, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFolds(t, folds, 11)
+	if got := testSizes(folds); !reflect.DeepEqual(got, []int{4, 4, 3}) {
+		t.Errorf("test sizes %v, want [4 4 3]", got)
+	}
+	if !reflect.DeepEqual(folds[1].Test, []int{4, 5, 6, 7}) {
+		t.Errorf("unshuffled fold 1 tests %v", folds[1].Test)
+	}
+
+	seed := int64(3)
+	shuffled := &KFold{NSplits: 3, Shuffle: true, RandomState: &seed}
+	a, _ := shuffled.Split(X, nil, nil)
+	b, _ := shuffled.Split(X, nil, nil)
+	checkFolds(t, a, 11)
+	if !reflect.DeepEqual(a, b) {
+		t.Error("the same RandomState gave different folds")
+	}
+	if reflect.DeepEqual(a, folds) {
+		t.Error("shuffled folds match the unshuffled ones")
+	}
+
+	for _, k := range []int{1, 12} {
+		if _, err := NewKFold(k).Split(X, nil, nil); err == nil {
+			t.Errorf("NSplits %d: expected an error", k)
+		}
+	}
+	if _, err := NewKFold(12).Split(X, nil, nil); !errors.Is(err, validation.ErrTooFewSamples) {
+		t.Errorf("err = %v, want ErrTooFewSamples", err)
+	}
+}
+
+func TestStratifiedKFold(t *testing.T) {
+	// Classes of 9, 6 and 3 rows, interleaved
+	y := make([]float64, 18)
+	for i := range y {
+		switch {
+		case i%6 < 3:
+			y[i] = 0
+		case i%6 < 5:
+			y[i] = 1
+		default:
+			y[i] = 2
+		}
+	}
+	X := matrix.Zeros(len(y), 1)
+
+	seed := int64(5)
+	for _, skf := range []*StratifiedKFold{NewStratifiedKFold(3), {NSplits: 3, Shuffle: true, RandomState: &seed}} {
+		folds, err := skf.Split(X, y, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkFolds(t, folds, len(y))
+
+		for k, fold := range folds {
+			counts := map[float64]int{}
+			for _, i := range fold.Test {
+				counts[y[i]]++
+			}
+			if want := map[float64]int{0: 3, 1: 2, 2: 1}; !reflect.DeepEqual(counts, want) {
+				t.Errorf("shuffle %v, fold %d: class counts %v, want %v", skf.Shuffle, k, counts, want)
+			}
+		}
+	}
+
+	if _, err := NewStratifiedKFold(3).Split(X, y[:5], nil); !errors.Is(err, validation.ErrShapeMismatch) {
+		t.Errorf("err = %v, want ErrShapeMismatch", err)
+	}
+}
+
+func TestGroupKFold(t *testing.T) {
+	// Groups of sizes 5, 4, 3, 2, 1, 1
+	groups := []int{0, 0, 0, 0, 0, 1, 1, 1, 1, 2, 2, 2, 3, 3, 4, 5}
+	X := matrix.Zeros(len(groups), 1)
+
+	folds, err := NewGroupKFold(3).Split(X, nil, groups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFolds(t, folds, len(groups))
+
+	for k, fold := range folds {
+		inTest := map[int]bool{}
+		for _, i := range fold.Test {
+			inTest[groups[i]] = true
+		}
+		for _, i := range fold.Train {
+			if inTest[groups[i]] {
+				t.Fatalf("fold %d: group %d is in train and test", k, groups[i])
+			}
+		}
+	}
+	// Largest first, each to the lightest fold: 5+1 | 4+1 | 3+2
+	if got := testSizes(folds); !reflect.DeepEqual(got, []int{6, 5, 5}) {
+		t.Errorf("test sizes %v, want [6 5 5]", got)
+	}
+
+	if _, err := NewGroupKFold(7).Split(X, nil, groups); err == nil {
+		t.Error("expected an error for more splits than groups")
+	}
+	if _, err := NewGroupKFold(2).Split(X, nil, groups[:3]); !errors.Is(err, validation.ErrShapeMismatch) {
+		t.Errorf("err = %v, want ErrShapeMismatch", err)
+	}
+}
+
+func TestRepeatedKFold(t *testing.T) {
+	X := matrix.Zeros(10, 1)
+	seed := int64(9)
+	rkf := &RepeatedKFold{NSplits: 5, NRepeats: 3, RandomState: &seed}
+
+	folds, err := rkf.Split(X, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(folds) != 15 {
+		t.Fatalf("%d folds, want 15", len(folds))
+	}
+	for r := 0; r < 3; r++ {
+		checkFolds(t, folds[5*r:5*r+5], 10)
+	}
+	if reflect.DeepEqual(folds[:5], folds[5:10]) {
+		t.Error("repeats used the same permutation")
+	}
+
+	again, _ := rkf.Split(X, nil, nil)
+	if !reflect.DeepEqual(folds, again) {
+		t.Error("the same RandomState gave different folds")
+	}
+
+	if _, err := NewRepeatedKFold(5, 0).Split(X, nil, nil); err == nil {
+		t.Error("expected an error for NRepeats 0")
+	}
+}
+
+func TestLeaveOneOut(t *testing.T) {
+	folds, err := LeaveOneOut{}.Split(matrix.Zeros(4, 1), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFolds(t, folds, 4)
+	for k, fold := range folds {
+		if !reflect.DeepEqual(fold.Test, []int{k}) {
+			t.Errorf("fold %d tests %v", k, fold.Test)
+		}
+	}
+
+	if _, err := (LeaveOneOut{}).Split(matrix.Zeros(1, 1), nil, nil); err == nil {
+		t.Error("expected an error for a single sample")
+	}
+}
+
+func TestTimeSeriesSplit(t *testing.T) {
+	X := matrix.Zeros(10, 1)
+
+	tests := []struct {
+		name  string
+		ts    *TimeSeriesSplit
+		train [][]int
+		test  [][]int
+	}{
+		{
+			name:  "expanding",
+			ts:    NewTimeSeriesSplit(3),
+			train: [][]int{{0, 1, 2, 3}, {0, 1, 2, 3, 4, 5}, {0, 1, 2, 3, 4, 5, 6, 7}},
+			test:  [][]int{{4, 5}, {6, 7}, {8, 9}},
+		},
+		{
+			name:  "gap and max train size",
+			ts:    &TimeSeriesSplit{NSplits: 3, Gap: 1, MaxTrainSize: 3},
+			train: [][]int{{0, 1, 2}, {2, 3, 4}, {4, 5, 6}},
+			test:  [][]int{{4, 5}, {6, 7}, {8, 9}},
+		},
+	}
+
+	for _, tt := range tests {
+		folds, err := tt.ts.Split(X, nil, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		for k, fold := range folds {
+			if !reflect.DeepEqual(fold.Train, tt.train[k]) || !reflect.DeepEqual(fold.Test, tt.test[k]) {
+				t.Errorf("%s: fold %d = %v / %v, want %v / %v", tt.name, k, fold.Train, fold.Test, tt.train[k], tt.test[k])
+			}
+		}
+	}
+
+	for _, ts := range []*TimeSeriesSplit{{NSplits: 0}, {NSplits: 3, Gap: -1}, {NSplits: 3, Gap: 4}, {NSplits: 10}} {
+		if _, err := ts.Split(X, nil, nil); err == nil {
+			t.Errorf("%+v: expected an error", *ts)
+		}
+	}
+}
@@ -2,21 +2,25 @@ package naivebayes
 
 import (
 	"os"
-	"errors"
 	"math"
-	"sort"
 	"encoding/gob"
 
 	"golearn-lite/core"
 	"golearn-lite/matrix"
+	"golearn-lite/validation"
 )
 
 
+func init() {
+	gob.Register(&GaussianNB{})
+}
+
+
 type GaussianNB struct {
 	ClassPriors			map[float64]float64
 	Means				map[float64][]float64
 	Variances			map[float64][]float64
-	Classes				[]float64
+	ClassLabels			[]float64
 	Epsilon				float64
 }
 
@@ -31,33 +35,31 @@ func NewGaussianNB() *GaussianNB {
 }
 
 func (gnb *GaussianNB) Fit(X matrix.Matrix, y []float64) error {
-	if X.Rows != len(y) {
-		return errors.New("number of samples in X and y must match")
+	if err := validation.CheckXY(X, y); err != nil {
+		return err
 	}
 
+	gnb.ClassPriors = make(map[float64]float64)
+	gnb.Means = make(map[float64][]float64)
+	gnb.Variances = make(map[float64][]float64)
+	gnb.ClassLabels = nil
+
 	nSamples, nFeatures := X.Rows, X.Cols
 	classCounts := make(map[float64]int)
 
 	// Get class labels
-	classSet := make(map[float64]bool)
-
-	for _, label := range y {
-		classSet[label] = true
-	}
-
-	for label := range classSet {
-		gnb.Classes = append(gnb.Classes, label)
-	}
+	gnb.ClassLabels = core.UniqueClasses(y)
 
 	// compute means and variance
-	for _, class := range gnb.Classes {
+	for _, class := range gnb.ClassLabels {
 		classCounts[class] = 0
 		means := make([]float64, nFeatures)
 		vars := make([]float64, nFeatures)
 
 		// First pass: compute sums
-		for i, row := range X.Data {
+		for i := 0; i < nSamples; i++ {
 			if y[i] == class {
+				row := X.Row(i)
 				classCounts[class]++
 				for j := 0; j < nFeatures; j++ {
 					means[j] += row[j]
@@ -69,8 +71,9 @@ func (gnb *GaussianNB) Fit(X matrix.Matrix, y []float64) error {
 			means[j] /= float64(classCounts[class])
 		}
 
-		for i, row := range X.Data {
+		for i := 0; i < nSamples; i++ {
 			if y[i] == class {
+				row := X.Row(i)
 				for j := 0; j < nFeatures; j++ {
 					diff := row[j] - means[j]
 					vars[j] += diff * diff
@@ -95,34 +98,76 @@ func (gnb *GaussianNB) Fit(X matrix.Matrix, y []float64) error {
 }
 
 func (gnb *GaussianNB) Predict(X matrix.Matrix) []float64 {
+	preds, _ := gnb.PredictE(X)
+	return preds
+}
+
+func (gnb *GaussianNB) PredictE(X matrix.Matrix) ([]float64, error) {
+	jll, err := gnb.jointLogLikelihood(X)
+	if err != nil {
+		return nil, err
+	}
+
+	return labelsOfMax(jll, gnb.ClassLabels), nil
+}
+
+func (gnb *GaussianNB) PredictProba(X matrix.Matrix) (matrix.Matrix, error) {
+	jll, err := gnb.jointLogLikelihood(X)
+	if err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	softmaxRows(jll)
+	return jll, nil
+}
+
+func (gnb *GaussianNB) Classes() []float64 {
+	return append([]float64(nil), gnb.ClassLabels...)
+}
+
+func (gnb *GaussianNB) IsClassifier() bool {
+	return true
+}
+
+// jointLogLikelihood returns log P(c) + log P(x | c) for every sample and
+// class, one column per entry of ClassLabels.
+func (gnb *GaussianNB) jointLogLikelihood(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := validation.CheckFitted(gnb.IsFitted()); err != nil {
+		return matrix.Matrix{}, err
+	}
+	if err := validation.CheckPredict(X, len(gnb.Means[gnb.ClassLabels[0]])); err != nil {
+		return matrix.Matrix{}, err
+	}
+
 	n := X.Rows
-	preds := make([]float64, n)
+	jll := matrix.Zeros(n, len(gnb.ClassLabels))
 
 	for i := 0; i < n; i++ {
-		x := X.Data[i]
-		scores := make(map[float64]float64)
+		x := X.Row(i)
+		scores := jll.Row(i)
 
-		for _, class := range gnb.Classes {
+		for k, class := range gnb.ClassLabels {
 			logProb := math.Log(gnb.ClassPriors[class])
 			for j := 0; j < len(x); j++ {
 				mu := gnb.Means[class][j]
 				variance := gnb.Variances[class][j] + gnb.Epsilon
 				diff := x[j] - mu
-				logProb += -0.3 * math.Log(2 * math.Pi * variance) - (diff * diff) / (2 * variance)
+				logProb += -0.5 * math.Log(2 * math.Pi * variance) - (diff * diff) / (2 * variance)
 			}
 
-			scores[class] = logProb
+			scores[k] = logProb
 		}
-
-		preds[i] = argmax(scores)
 	}
 
-	return preds
+	return jll, nil
+}
+
+func (gnb *GaussianNB) IsFitted() bool {
+	return len(gnb.ClassLabels) > 0
 }
 
 func (gnb *GaussianNB) Score(X matrix.Matrix, y []float64, metric func(yTrue, yPred []float64) float64) float64 {
-	yPred := gnb.Predict(X)
-	return metric(y, yPred)
+	return core.Score(gnb.PredictE, X, y, metric)
 }
 
 func (gnb *GaussianNB) Save(path string) error {
@@ -136,44 +181,81 @@ func (gnb *GaussianNB) Save(path string) error {
 }
 
 func (gnb *GaussianNB) Load(path string) error {
-	f, err := os.Open(path)
-	if err != nil {
+	if err := core.LoadGob(path, gnb); err != nil {
 		return err
 	}
-	defer f.Close()
+	if !gnb.IsFitted() {
+		gnb.ClassLabels = legacyClassLabels(path)
+	}
 
-	return gob.NewDecoder(f).Decode(gnb)
+	return nil
 }
 
 func (gnb *GaussianNB) GetParams() map[string]interface{} {
-	return map[string]interface{}{}
+	return map[string]interface{} {
+		"epsilon": gnb.Epsilon,
+	}
 }
 
-func (gnb *GaussianNB) SetParams(map[string]interface{}) error {
+func (gnb *GaussianNB) SetParams(params map[string]interface{}) error {
+	if v, ok := params["epsilon"].(float64); ok {
+		gnb.Epsilon = v
+	}
+
 	return nil
 }
 
-func argmax(m map[float64]float64) float64 {
-	type kv struct {
-		Key 	float64
-		Value 	float64
+// legacyClassLabels reads the labels that files saved before Classes became
+// a method hold in a field named Classes, or returns nil if there is none.
+func legacyClassLabels(path string) []float64 {
+	var legacy struct {
+		Classes []float64
+	}
+	if err := core.LoadGob(path, &legacy); err != nil {
+		return nil
 	}
 
-	var sorted []kv
+	return core.UniqueClasses(legacy.Classes)
+}
+
+// labelsOfMax maps the best-scoring column of each row to its class label.
+func labelsOfMax(scores matrix.Matrix, classes []float64) []float64 {
+	best, _ := scores.ArgMaxAxis(matrix.AxisCols)
+	preds := make([]float64, len(best))
 
-	for k, v := range m {
-		sorted = append(sorted, kv{k, v})
+	for i, k := range best {
+		preds[i] = classes[k]
 	}
 
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].Value > sorted[j].Value
-	})
+	return preds
+}
+
+// softmaxRows turns each row of log scores into probabilities in place,
+// subtracting the row maximum first so exp cannot overflow.
+func softmaxRows(m matrix.Matrix) {
+	for i := 0; i < m.Rows; i++ {
+		row := m.Row(i)
+		maxScore := math.Inf(-1)
+		for _, v := range row {
+			maxScore = math.Max(maxScore, v)
+		}
 
-	return sorted[0].Key
+		sum := 0.0
+		for j, v := range row {
+			row[j] = math.Exp(v - maxScore)
+			sum += row[j]
+		}
+		for j := range row {
+			row[j] /= sum
+		}
+	}
 }
 
 
 var _ core.Model = (*GaussianNB)(nil)
+var _ core.Estimator = (*GaussianNB)(nil)
 var _ core.Scorable = (*GaussianNB)(nil)
 var _ core.Serializable = (*GaussianNB)(nil)
-var _ core.Params = (*GaussianNB)(nil)
\ No newline at end of file
+var _ core.Params = (*GaussianNB)(nil)
+var _ core.ProbabilisticClassifier = (*GaussianNB)(nil)
+var _ core.Classifier = (*GaussianNB)(nil)
@@ -8,13 +8,19 @@ import (
 
 	"golearn-lite/core"
 	"golearn-lite/matrix"
+	"golearn-lite/validation"
 )
 
 
+func init() {
+	gob.Register(&MultinomialNB{})
+}
+
+
 type MultinomialNB struct {
 	ClassPriors			map[float64]float64
 	FeatureLogProbs		map[float64][]float64	// P(x_j | c)
-	Classes				[]float64
+	ClassLabels			[]float64
 	Alpha				float64
 }
 
@@ -28,34 +34,52 @@ func NewMultinomialNB() *MultinomialNB {
 }
 
 func (mnb *MultinomialNB) Fit(X matrix.Matrix, y []float64) error {
-	if X.Rows != len(y) {
-		return errors.New("number of samples in X and y must match")
+	return mnb.FitMat(X, y)
+}
+
+// FitMat fits on any dense or sparse matrix; only non-zero counts are visited.
+func (mnb *MultinomialNB) FitMat(X matrix.Mat, y []float64) error {
+	if err := validation.CheckXY(X, y); err != nil {
+		return err
 	}
 
-	nSamples, nFeatures := X.Rows, X.Cols
+	nSamples, nFeatures := X.Dims()
+	X = matrix.RowMajor(X)
+
+	negative := false
+	for i := 0; i < nSamples && !negative; i++ {
+		X.DoRowNonZero(i, func(_ int, v float64) {
+			negative = negative || v < 0
+		})
+	}
+	if negative {
+		return errors.New("multinomial naive bayes requires non-negative features")
+	}
+
+	mnb.ClassPriors = make(map[float64]float64)
+	mnb.FeatureLogProbs = make(map[float64][]float64)
+	mnb.ClassLabels = nil
+
 	classCounts := make(map[float64]int)
 	classSums := make(map[float64][]float64)
-	classSet := make(map[float64]bool)
 
-	for i, row := range X.Data {
+	for i := 0; i < nSamples; i++ {
 		label := y[i]
-		classSet[label] = true
 		classCounts[label]++
 
 		if _, exists := classSums[label]; !exists {
 			classSums[label] = make([]float64, nFeatures)
 		}
 
-		for j := 0; j < nFeatures; j++ {
-			classSums[label][j] += row[j]
-		}
+		sums := classSums[label]
+		X.DoRowNonZero(i, func(j int, v float64) {
+			sums[j] += v
+		})
 	}
 
-	for class := range classSet {
-		mnb.Classes = append(mnb.Classes, class)
-	}
+	mnb.ClassLabels = core.UniqueClasses(y)
 
-	for _, class := range mnb.Classes {
+	for _, class := range mnb.ClassLabels {
 		sums := classSums[class]
 		total := 0.0
 		for _, val := range sums {
@@ -77,30 +101,85 @@ func (mnb *MultinomialNB) Fit(X matrix.Matrix, y []float64) error {
 }
 
 func (mnb *MultinomialNB) Predict(X matrix.Matrix) []float64 {
-	n := X.Rows
-	preds := make([]float64, n)
+	return mnb.PredictMat(X)
+}
+
+func (mnb *MultinomialNB) PredictMat(X matrix.Mat) []float64 {
+	preds, _ := mnb.predict(X)
+	return preds
+}
+
+func (mnb *MultinomialNB) PredictE(X matrix.Matrix) ([]float64, error) {
+	return mnb.predict(X)
+}
+
+func (mnb *MultinomialNB) IsFitted() bool {
+	return len(mnb.ClassLabels) > 0
+}
+
+func (mnb *MultinomialNB) PredictProba(X matrix.Matrix) (matrix.Matrix, error) {
+	return mnb.PredictProbaMat(X)
+}
+
+func (mnb *MultinomialNB) PredictProbaMat(X matrix.Mat) (matrix.Matrix, error) {
+	jll, err := mnb.jointLogLikelihood(X)
+	if err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	softmaxRows(jll)
+	return jll, nil
+}
+
+func (mnb *MultinomialNB) Classes() []float64 {
+	return append([]float64(nil), mnb.ClassLabels...)
+}
+
+func (mnb *MultinomialNB) IsClassifier() bool {
+	return true
+}
+
+func (mnb *MultinomialNB) predict(X matrix.Mat) ([]float64, error) {
+	jll, err := mnb.jointLogLikelihood(X)
+	if err != nil {
+		return nil, err
+	}
+
+	return labelsOfMax(jll, mnb.ClassLabels), nil
+}
+
+// jointLogLikelihood returns log P(c) + Σ x_j log P(j | c) for every sample
+// and class, one column per entry of ClassLabels.
+func (mnb *MultinomialNB) jointLogLikelihood(X matrix.Mat) (matrix.Matrix, error) {
+	if err := validation.CheckFitted(mnb.IsFitted()); err != nil {
+		return matrix.Matrix{}, err
+	}
+	if err := validation.CheckPredict(X, len(mnb.FeatureLogProbs[mnb.ClassLabels[0]])); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	n, _ := X.Dims()
+	X = matrix.RowMajor(X)
+	jll := matrix.Zeros(n, len(mnb.ClassLabels))
 
 	for i := 0; i < n; i++ {
-		x := X.Data[i]
-		scores := make(map[float64]float64)
+		scores := jll.Row(i)
 
-		for _, class := range mnb.Classes {
+		for k, class := range mnb.ClassLabels {
 			logProb := math.Log(mnb.ClassPriors[class])
-			for j := 0; j < len(x); j++ {
-				logProb += x[j] * mnb.FeatureLogProbs[class][j]
-			}
-			scores[class] = logProb
+			featureLogProbs := mnb.FeatureLogProbs[class]
+			X.DoRowNonZero(i, func(j int, v float64) {
+				logProb += v * featureLogProbs[j]
+			})
+			scores[k] = logProb
 		}
-
-		preds[i] = argmax(scores)
 	}
 
-	return preds
+	return jll, nil
 }
 
 func (mnb *MultinomialNB) Score(X matrix.Matrix, y []float64, metric func(yTrue, yPred []float64) float64) float64 {
-	yPred := mnb.Predict(X)
-	return metric(y, yPred)
+	return core.Score(mnb.PredictE, X, y, metric)
 }
 
 func (mnb *MultinomialNB) Save(path string) error {
@@ -114,13 +193,14 @@ func (mnb *MultinomialNB) Save(path string) error {
 }
 
 func (mnb *MultinomialNB) Load(path string) error {
-	f, err := os.Open(path)
-	if err != nil {
+	if err := core.LoadGob(path, mnb); err != nil {
 		return err
 	}
-	defer f.Close()
+	if !mnb.IsFitted() {
+		mnb.ClassLabels = legacyClassLabels(path)
+	}
 
-	return gob.NewDecoder(f).Decode(mnb)
+	return nil
 }
 
 func (mnb *MultinomialNB) GetParams() map[string]interface{} {
@@ -139,8 +219,9 @@ func (mnb *MultinomialNB) SetParams(params map[string]interface{}) error {
 
 
 var _ core.Model = (*MultinomialNB)(nil)
+var _ core.Estimator = (*MultinomialNB)(nil)
 var _ core.Scorable = (*MultinomialNB)(nil)
 var _ core.Serializable = (*MultinomialNB)(nil)
 var _ core.Params = (*MultinomialNB)(nil)
-
-
+var _ core.ProbabilisticClassifier = (*MultinomialNB)(nil)
+var _ core.Classifier = (*MultinomialNB)(nil)
@@ -0,0 +1,108 @@
+package naivebayes
+
+import (
+	"encoding/gob"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golearn-lite/matrix"
+)
+
+func TestGaussianNBPredict(t *testing.T) {
+	X := matrix.New([][]float64{{0, 0}, {0.2, 0.1}, {-0.1, 0.2}, {5, 5}, {5.2, 4.9}, {4.8, 5.1}})
+	y := []float64{0, 0, 0, 1, 1, 1}
+
+	gnb := NewGaussianNB()
+	if err := gnb.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	preds, err := gnb.PredictE(matrix.New([][]float64{{0.1, 0}, {5, 5.1}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preds[0] != 0 || preds[1] != 1 {
+		t.Errorf("predictions %v, want [0 1]", preds)
+	}
+
+	proba, err := gnb.PredictProba(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < proba.Rows; i++ {
+		if sum := proba.At(i, 0) + proba.At(i, 1); math.Abs(sum-1) > 1e-12 {
+			t.Errorf("row %d probabilities sum to %v", i, sum)
+		}
+	}
+}
+
+// writeGob saves v the way Save does, for file layouts older than the
+// current types.
+func writeGob(t *testing.T, v interface{}) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "model.gob")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := gob.NewEncoder(f).Encode(v); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadLegacyClassesField(t *testing.T) {
+	gaussian := writeGob(t, struct {
+		ClassPriors map[float64]float64
+		Means       map[float64][]float64
+		Variances   map[float64][]float64
+		Classes     []float64
+		Epsilon     float64
+	}{
+		ClassPriors: map[float64]float64{1: 0.5, 0: 0.5},
+		Means:       map[float64][]float64{0: {0}, 1: {5}},
+		Variances:   map[float64][]float64{0: {1}, 1: {1}},
+		Classes:     []float64{1, 0},
+		Epsilon:     1e-9,
+	})
+
+	gnb := &GaussianNB{}
+	if err := gnb.Load(gaussian); err != nil {
+		t.Fatal(err)
+	}
+	preds, err := gnb.PredictE(matrix.New([][]float64{{0.2}, {4.9}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preds[0] != 0 || preds[1] != 1 {
+		t.Errorf("GaussianNB predictions %v, want [0 1]", preds)
+	}
+
+	multinomial := writeGob(t, struct {
+		ClassPriors     map[float64]float64
+		FeatureLogProbs map[float64][]float64
+		Classes         []float64
+		Alpha           float64
+	}{
+		ClassPriors:     map[float64]float64{0: 0.5, 1: 0.5},
+		FeatureLogProbs: map[float64][]float64{0: {math.Log(0.9), math.Log(0.1)}, 1: {math.Log(0.1), math.Log(0.9)}},
+		Classes:         []float64{0, 1},
+		Alpha:           1,
+	})
+
+	mnb := NewMultinomialNB()
+	if err := mnb.Load(multinomial); err != nil {
+		t.Fatal(err)
+	}
+	preds, err = mnb.PredictE(matrix.New([][]float64{{3, 0}, {0, 3}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preds[0] != 0 || preds[1] != 1 {
+		t.Errorf("MultinomialNB predictions %v, want [0 1]", preds)
+	}
+}
@@ -9,12 +9,21 @@ import (
 
 	"golearn-lite/core"
 	"golearn-lite/matrix"
+	"golearn-lite/validation"
 )
 
 
+func init() {
+	gob.Register(&KNN{})
+}
+
+
 type KNN struct {
 	XTrain 		[][]float64
-	yTrain 		[]float64
+	XSparse		*matrix.Sparse		// set instead of XTrain when fitted on sparse input
+	XTrain32	*matrix.Matrix32	// set instead of XTrain when fitted on float32 input
+	YTrain 		[]float64
+	ClassLabels	[]float64			// distinct training labels, classification only
 	K			int
 	Task		string
 }
@@ -33,24 +42,70 @@ func NewKNN(k int, task string) *KNN {
 }
 
 func (knn *KNN) Fit(X matrix.Matrix, y []float64) error {
-	if X.Rows != len(y) {
-		return errors.New("number of samples in X and y must match")
+	return knn.FitMat(X, y)
+}
+
+// FitMat stores the training set. Sparse input stays sparse (as CSR) so
+// distances only touch non-zeros.
+func (knn *KNN) FitMat(X matrix.Mat, y []float64) error {
+	if knn.K < 1 {
+		return errors.New("k must be at least 1")
+	}
+	if err := validation.CheckXY(X, y); err != nil {
+		return err
 	}
 
-	knn.XTrain = X.Data
-	knn.yTrain = y
+	rows, _ := X.Dims()
+	if err := validation.CheckMinSamples(rows, knn.K); err != nil {
+		return err
+	}
+
+	knn.XTrain, knn.XSparse, knn.XTrain32 = nil, nil, nil
+	switch x := X.(type) {
+	case *matrix.Sparse:
+		knn.XSparse = x.ToCSR()
+	case matrix.Matrix32:
+		train := x.Copy()
+		knn.XTrain32 = &train
+	case matrix.Matrix:
+		knn.XTrain = x.ToSlices()
+	default:
+		knn.XTrain = toSlices(X)
+	}
+	knn.YTrain = y
+	knn.ClassLabels = nil
+	if knn.Task == "classification" {
+		knn.ClassLabels = core.UniqueClasses(y)
+	}
 
 	return nil
 }
 
 func (knn *KNN) Predict(X matrix.Matrix) []float64 {
-	n := X.Rows
-	preds := make([]float64, n)
+	return knn.PredictMat(X)
+}
 
-	for i := 0; i < n; i++ {
-		x := X.Data[i]
-		neighbors := knn.getKNearest(x)
+func (knn *KNN) PredictMat(X matrix.Mat) []float64 {
+	preds, _ := knn.predict(X)
+	return preds
+}
 
+func (knn *KNN) PredictE(X matrix.Matrix) ([]float64, error) {
+	return knn.predict(X)
+}
+
+func (knn *KNN) IsFitted() bool {
+	return knn.XTrain != nil || knn.XSparse != nil || knn.XTrain32 != nil
+}
+
+func (knn *KNN) predict(X matrix.Mat) ([]float64, error) {
+	nearest, err := knn.kNearest(X)
+	if err != nil {
+		return nil, err
+	}
+
+	preds := make([]float64, len(nearest))
+	for i, neighbors := range nearest {
 		if knn.Task == "classification" {
 			preds[i] = majorityVote(neighbors)
 		} else {
@@ -58,12 +113,105 @@ func (knn *KNN) Predict(X matrix.Matrix) []float64 {
 		}
 	}
 
-	return preds
+	return preds, nil
+}
+
+func (knn *KNN) PredictProba(X matrix.Matrix) (matrix.Matrix, error) {
+	return knn.PredictProbaMat(X)
+}
+
+// PredictProbaMat reports the share of the K neighbors in each class.
+func (knn *KNN) PredictProbaMat(X matrix.Mat) (matrix.Matrix, error) {
+	if knn.Task != "classification" {
+		return matrix.Matrix{}, errors.New("PredictProba requires a classification task")
+	}
+	if knn.IsFitted() && len(knn.ClassLabels) == 0 {
+		// Task was switched to classification after a regression Fit
+		return matrix.Matrix{}, errors.New("PredictProba requires a KNN fitted for classification")
+	}
+
+	nearest, err := knn.kNearest(X)
+	if err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	column := make(map[float64]int, len(knn.ClassLabels))
+	for k, class := range knn.ClassLabels {
+		column[class] = k
+	}
+
+	proba := matrix.Zeros(len(nearest), len(knn.ClassLabels))
+	for i, neighbors := range nearest {
+		row := proba.Row(i)
+		for _, n := range neighbors {
+			row[column[n.label]] += 1 / float64(len(neighbors))
+		}
+	}
+
+	return proba, nil
+}
+
+func (knn *KNN) Classes() []float64 {
+	return append([]float64(nil), knn.ClassLabels...)
+}
+
+func (knn *KNN) IsClassifier() bool {
+	return knn.Task == "classification"
+}
+
+func (knn *KNN) IsRegressor() bool {
+	return knn.Task != "classification"
+}
+
+// kNearest validates X and returns the K nearest training samples of each row.
+func (knn *KNN) kNearest(X matrix.Mat) ([][]neighbor, error) {
+	if err := validation.CheckFitted(knn.IsFitted()); err != nil {
+		return nil, err
+	}
+	if err := validation.CheckPredict(X, knn.nFeatures()); err != nil {
+		return nil, err
+	}
+	if err := validation.CheckMinSamples(len(knn.YTrain), knn.K); err != nil {
+		return nil, err
+	}
+
+	n, cols := X.Dims()
+	X = matrix.RowMajor(X)
+	nearest := make([][]neighbor, n)
+	x := make([]float64, cols)
+
+	var trainNorms []float64
+	if knn.XSparse != nil {
+		trainNorms = sparseRowNorms(knn.XSparse)
+	}
+
+	for i := 0; i < n; i++ {
+		for j := range x {
+			x[j] = 0
+		}
+		X.DoRowNonZero(i, func(j int, v float64) {
+			x[j] = v
+		})
+
+		nearest[i] = knn.getKNearest(x, trainNorms)
+	}
+
+	return nearest, nil
+}
+
+func (knn *KNN) nFeatures() int {
+	switch {
+	case knn.XSparse != nil:
+		return knn.XSparse.Cols
+	case knn.XTrain32 != nil:
+		return knn.XTrain32.Cols
+	default:
+		return len(knn.XTrain[0])
+	}
 }
 
 func (knn *KNN) Score(X matrix.Matrix, y []float64, metric func(yTrue, yPred []float64) float64) float64 {
-	yPred := knn.Predict(X)
-	return metric(y, yPred)
+	return core.Score(knn.PredictE, X, y, metric)
 }
 
 func (knn *KNN) Save(path string) error {
@@ -77,13 +225,7 @@ func (knn *KNN) Save(path string) error {
 }
 
 func (knn *KNN) Load(path string) error {
-	f, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	return gob.NewDecoder(f).Decode(knn)
+	return core.LoadGob(path, knn)
 }
 
 func (knn *KNN) GetParams() map[string]interface{} {
@@ -104,12 +246,37 @@ func (knn *KNN) SetParams(params map[string]interface{}) error {
 	return nil
 }
 
-func (knn *KNN) getKNearest(x []float64) []neighbor {
-	all := make([]neighbor, len(knn.XTrain))
+func (knn *KNN) getKNearest(x []float64, trainNorms []float64) []neighbor {
+	var all []neighbor
+
+	if knn.XSparse != nil {
+		// ‖x - t‖² = ‖x‖² + ‖t‖² - 2·x·t, where x·t only visits t's non-zeros
+		xNorm := 0.0
+		for _, v := range x {
+			xNorm += v * v
+		}
 
-	for i, trainX := range knn.XTrain {
-		dist := euclideanDisttance(x, trainX)
-		all[i] = neighbor{distance: dist, label: knn.yTrain[i]}
+		all = make([]neighbor, knn.XSparse.Rows)
+		for i := range all {
+			dot := 0.0
+			knn.XSparse.DoRowNonZero(i, func(j int, v float64) {
+				dot += x[j] * v
+			})
+			sq := math.Max(0, xNorm+trainNorms[i]-2*dot)
+			all[i] = neighbor{distance: math.Sqrt(sq), label: knn.YTrain[i]}
+		}
+	} else if knn.XTrain32 != nil {
+		all = make([]neighbor, knn.XTrain32.Rows)
+		for i := range all {
+			dist := euclideanDisttance(x, knn.XTrain32.Row(i))
+			all[i] = neighbor{distance: dist, label: knn.YTrain[i]}
+		}
+	} else {
+		all = make([]neighbor, len(knn.XTrain))
+		for i, trainX := range knn.XTrain {
+			dist := euclideanDisttance(x, trainX)
+			all[i] = neighbor{distance: dist, label: knn.YTrain[i]}
+		}
 	}
 
 	sort.Slice(all, func(i, j int) bool {
@@ -119,11 +286,39 @@ func (knn *KNN) getKNearest(x []float64) []neighbor {
 	return all[:knn.K]
 }
 
-func euclideanDisttance(a, b []float64) float64 {
+func sparseRowNorms(s *matrix.Sparse) []float64 {
+	norms := make([]float64, s.Rows)
+
+	for i := range norms {
+		s.DoRowNonZero(i, func(_ int, v float64) {
+			norms[i] += v * v
+		})
+	}
+
+	return norms
+}
+
+func toSlices(X matrix.Mat) [][]float64 {
+	rows, cols := X.Dims()
+	X = matrix.RowMajor(X)
+	out := make([][]float64, rows)
+
+	for i := range out {
+		row := make([]float64, cols)
+		X.DoRowNonZero(i, func(j int, v float64) {
+			row[j] = v
+		})
+		out[i] = row
+	}
+
+	return out
+}
+
+func euclideanDisttance[T matrix.Float](a []float64, b []T) float64 {
 	sum := 0.0
 
 	for i := range a {
-		diff := a[i] - b[i]
+		diff := a[i] - float64(b[i])
 		sum += diff * diff
 	}
 
@@ -162,6 +357,10 @@ func meanVote(neighbors []neighbor) float64 {
 
 
 var _ core.Model = (*KNN)(nil)
+var _ core.Estimator = (*KNN)(nil)
 var _ core.Scorable = (*KNN)(nil)
 var _ core.Serializable = (*KNN)(nil)
-var _ core.Params = (*KNN)(nil)
\ No newline at end of file
+var _ core.Params = (*KNN)(nil)
+var _ core.ProbabilisticClassifier = (*KNN)(nil)
+var _ core.Classifier = (*KNN)(nil)
+var _ core.Regressor = (*KNN)(nil)
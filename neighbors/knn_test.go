@@ -0,0 +1,85 @@
+package neighbors
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"golearn-lite/matrix"
+	"golearn-lite/validation"
+)
+
+func TestKNNPredictProba(t *testing.T) {
+	X := matrix.New([][]float64{{0}, {0.1}, {0.2}, {5}, {5.1}})
+	y := []float64{0, 0, 1, 1, 1}
+
+	knn := NewKNN(3, "classification")
+	if err := knn.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+
+	proba, err := knn.PredictProba(matrix.New([][]float64{{0}, {5}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]float64{{2.0 / 3, 1.0 / 3}, {0, 1}}
+	for i, row := range want {
+		for k, w := range row {
+			if diff := proba.At(i, k) - w; diff > 1e-12 || diff < -1e-12 {
+				t.Errorf("proba(%d, %d) = %v, want %v", i, k, proba.At(i, k), w)
+			}
+		}
+	}
+}
+
+func TestKNNPredictProbaErrors(t *testing.T) {
+	X := matrix.New([][]float64{{0}, {1}, {2}})
+	y := []float64{0.5, 1.5, 2.5}
+
+	if _, err := NewKNN(1, "classification").PredictProba(X); !errors.Is(err, validation.ErrNotFitted) {
+		t.Errorf("unfitted: err = %v, want ErrNotFitted", err)
+	}
+
+	// Fitted for regression, then switched to classification
+	knn := NewKNN(1, "regression")
+	if err := knn.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if err := knn.SetParams(map[string]interface{}{"task": "classification"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := knn.PredictProba(X); err == nil {
+		t.Error("expected an error after switching task without refitting")
+	}
+}
+
+func TestKNNLoadReplacesReceiver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "knn.gob")
+
+	sparse, err := matrix.NewSparseFromTriplets(matrix.CSR, 3, 2, []int{0, 1, 2}, []int{0, 1, 0}, []float64{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := NewKNN(1, "classification")
+	if err := saved.FitMat(sparse, []float64{0, 1, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := saved.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewKNN(2, "regression")
+	if err := loaded.Fit(matrix.New([][]float64{{0, 0}, {1, 1}}), []float64{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.Load(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.XTrain != nil || loaded.XSparse == nil {
+		t.Errorf("after Load XTrain = %v, XSparse = %v; want only XSparse", loaded.XTrain, loaded.XSparse)
+	}
+	if loaded.K != 1 || loaded.Task != "classification" {
+		t.Errorf("after Load K = %d, Task = %q", loaded.K, loaded.Task)
+	}
+}
@@ -0,0 +1,181 @@
+package pipeline
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+
+	"golearn-lite/core"
+	"golearn-lite/matrix"
+)
+
+
+func init() {
+	gob.Register(&Chain{})
+}
+
+
+// Chain applies its steps in order and is itself a Transformer, so a
+// sequence such as imputing then scaling can stand wherever a single
+// transformer is expected, for example in a ColumnSpec.
+type Chain struct {
+	Steps []Step
+}
+
+func NewChain(steps ...Step) *Chain {
+	return &Chain{Steps: steps}
+}
+
+func (c *Chain) Fit(X matrix.Matrix) error {
+	_, err := c.FitTransform(X)
+	return err
+}
+
+func (c *Chain) FitTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	names, err := stepNames(c.Steps)
+	if err != nil {
+		return matrix.Matrix{}, err
+	}
+	if err := checkNames(names); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	return fitTransformSteps(c.Steps, X)
+}
+
+func (c *Chain) Transform(X matrix.Matrix) (matrix.Matrix, error) {
+	return transformSteps(c.Steps, X)
+}
+
+// InverseTransform undoes the steps in reverse order.
+func (c *Chain) InverseTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	for k := len(c.Steps) - 1; k >= 0; k-- {
+		var err error
+		X, err = c.Steps[k].Transformer.InverseTransform(X)
+		if err != nil {
+			return matrix.Matrix{}, fmt.Errorf("step %q: %w", c.Steps[k].Name, err)
+		}
+	}
+
+	return X, nil
+}
+
+// Clone returns an unfitted copy with every step cloned.
+func (c *Chain) Clone() (core.Transformer, error) {
+	steps, err := cloneSteps(c.Steps)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Chain{Steps: steps}, nil
+}
+
+func (c *Chain) IsFitted() bool {
+	return stepsFitted(c.Steps)
+}
+
+func (c *Chain) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewEncoder(f).Encode(c)
+}
+
+func (c *Chain) Load(path string) error {
+	return core.LoadGob(path, c)
+}
+
+func (c *Chain) GetParams() map[string]interface{} {
+	return prefixedParams(c.components())
+}
+
+func (c *Chain) SetParams(params map[string]interface{}) error {
+	return setPrefixedParams(c.components(), params)
+}
+
+func (c *Chain) components() map[string]interface{} {
+	out := make(map[string]interface{}, len(c.Steps))
+
+	for _, step := range c.Steps {
+		out[step.Name] = step.Transformer
+	}
+
+	return out
+}
+
+func cloneSteps(steps []Step) ([]Step, error) {
+	out := make([]Step, len(steps))
+
+	for k, step := range steps {
+		t, err := core.CloneTransformer(step.Transformer)
+		if err != nil {
+			return nil, fmt.Errorf("step %q: %w", step.Name, err)
+		}
+		out[k] = Step{Name: step.Name, Transformer: t}
+	}
+
+	return out, nil
+}
+
+// fitTransformSteps fits each step on the output of the previous one.
+func fitTransformSteps(steps []Step, X matrix.Matrix) (matrix.Matrix, error) {
+	for _, step := range steps {
+		var err error
+		X, err = step.Transformer.FitTransform(X)
+		if err != nil {
+			return matrix.Matrix{}, fmt.Errorf("step %q: %w", step.Name, err)
+		}
+	}
+
+	return X, nil
+}
+
+// transformSteps applies fitted steps to X without refitting them.
+func transformSteps(steps []Step, X matrix.Matrix) (matrix.Matrix, error) {
+	for _, step := range steps {
+		var err error
+		X, err = step.Transformer.Transform(X)
+		if err != nil {
+			return matrix.Matrix{}, fmt.Errorf("step %q: %w", step.Name, err)
+		}
+	}
+
+	return X, nil
+}
+
+// stepsFitted reports whether every step that can tell is fitted.
+func stepsFitted(steps []Step) bool {
+	for _, step := range steps {
+		if f, ok := step.Transformer.(fittable); ok && !f.IsFitted() {
+			return false
+		}
+	}
+
+	return true
+}
+
+type fittable interface {
+	IsFitted() bool
+}
+
+func stepNames(steps []Step) ([]string, error) {
+	names := make([]string, 0, len(steps)+1)
+
+	for _, step := range steps {
+		if step.Transformer == nil {
+			return nil, fmt.Errorf("step %q has no transformer", step.Name)
+		}
+		names = append(names, step.Name)
+	}
+
+	return names, nil
+}
+
+
+var _ core.Transformer = (*Chain)(nil)
+var _ core.Serializable = (*Chain)(nil)
+var _ core.Params = (*Chain)(nil)
+var _ core.TransformerCloner = (*Chain)(nil)
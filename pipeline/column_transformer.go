@@ -0,0 +1,343 @@
+package pipeline
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	"golearn-lite/core"
+	"golearn-lite/matrix"
+	"golearn-lite/validation"
+)
+
+
+func init() {
+	gob.Register(&ColumnTransformer{})
+}
+
+
+// ColumnSpec applies Transformer to a subset of the input columns, chosen
+// by index in Columns and by name in ColumnNames. A nil Transformer passes
+// the selected columns through unchanged.
+type ColumnSpec struct {
+	Name        string
+	Transformer core.Transformer
+	Columns     []int
+	ColumnNames []string
+}
+
+// ColumnTransformer runs each spec on its own columns and concatenates the
+// outputs in spec order. Columns claimed by no spec are dropped, or
+// appended after the spec outputs when Remainder is "passthrough".
+// ColumnNames are resolved against FeatureNames during Fit.
+type ColumnTransformer struct {
+	Transformers []ColumnSpec
+	Remainder    string
+	FeatureNames []string
+
+	NFeatures        int
+	Selected         [][]int // resolved input columns of each spec
+	RemainderColumns []int
+	OutputWidths     []int // output columns produced by each spec
+}
+
+func NewColumnTransformer(specs []ColumnSpec, remainder string) *ColumnTransformer {
+	return &ColumnTransformer{
+		Transformers: specs,
+		Remainder:    remainder,
+	}
+}
+
+func (ct *ColumnTransformer) Fit(X matrix.Matrix) error {
+	_, err := ct.FitTransform(X)
+	return err
+}
+
+func (ct *ColumnTransformer) FitTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	if ct.Remainder != "drop" && ct.Remainder != "passthrough" {
+		return matrix.Matrix{}, errors.New(`remainder must be "drop" or "passthrough"`)
+	}
+	if err := validation.CheckMinSamples(X.Rows, 1); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	names := make([]string, len(ct.Transformers))
+	for k, spec := range ct.Transformers {
+		names[k] = spec.Name
+	}
+	if err := checkNames(names); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	selected, err := ct.resolve(X.Cols)
+	if err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	parts := make([]matrix.Matrix, 0, len(ct.Transformers)+1)
+	widths := make([]int, len(ct.Transformers))
+	for k, spec := range ct.Transformers {
+		out := X.SelectCols(selected[k])
+		if spec.Transformer != nil {
+			out, err = spec.Transformer.FitTransform(out)
+			if err != nil {
+				return matrix.Matrix{}, fmt.Errorf("step %q: %w", spec.Name, err)
+			}
+		}
+		parts = append(parts, out)
+		widths[k] = out.Cols
+	}
+
+	ct.NFeatures = X.Cols
+	ct.Selected = selected
+	ct.RemainderColumns = remainderColumns(X.Cols, selected)
+	ct.OutputWidths = widths
+
+	return ct.stack(X, parts)
+}
+
+func (ct *ColumnTransformer) Transform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := validation.CheckFitted(ct.IsFitted()); err != nil {
+		return matrix.Matrix{}, err
+	}
+	if err := validation.CheckFeatures(X, ct.NFeatures); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	parts := make([]matrix.Matrix, 0, len(ct.Transformers)+1)
+	for k, spec := range ct.Transformers {
+		out := X.SelectCols(ct.Selected[k])
+		if spec.Transformer != nil {
+			var err error
+			out, err = spec.Transformer.Transform(out)
+			if err != nil {
+				return matrix.Matrix{}, fmt.Errorf("step %q: %w", spec.Name, err)
+			}
+		}
+		if out.Cols != ct.OutputWidths[k] {
+			return matrix.Matrix{}, fmt.Errorf("step %q produced %d columns, %d during Fit", spec.Name, out.Cols, ct.OutputWidths[k])
+		}
+		parts = append(parts, out)
+	}
+
+	return ct.stack(X, parts)
+}
+
+// InverseTransform splits X back into the spec outputs, inverts each one
+// and writes it to its original columns. Dropped columns come back as NaN;
+// a column claimed by several specs takes the value of the last one.
+func (ct *ColumnTransformer) InverseTransform(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := validation.CheckFitted(ct.IsFitted()); err != nil {
+		return matrix.Matrix{}, err
+	}
+	if err := validation.CheckFeatures(X, ct.outputWidth()); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	out := matrix.Zeros(X.Rows, ct.NFeatures)
+	for i := range out.Data {
+		out.Data[i] = math.NaN()
+	}
+
+	offset := 0
+	for k, spec := range ct.Transformers {
+		block := X.Slice(0, X.Rows, offset, offset+ct.OutputWidths[k])
+		offset += ct.OutputWidths[k]
+
+		if spec.Transformer != nil {
+			var err error
+			block, err = spec.Transformer.InverseTransform(block)
+			if err != nil {
+				return matrix.Matrix{}, fmt.Errorf("step %q: %w", spec.Name, err)
+			}
+		}
+		scatterCols(out, block, ct.Selected[k])
+	}
+
+	if ct.Remainder == "passthrough" && len(ct.RemainderColumns) > 0 {
+		scatterCols(out, X.Slice(0, X.Rows, offset, X.Cols), ct.RemainderColumns)
+	}
+
+	return out, nil
+}
+
+// Clone returns an unfitted copy with every spec's transformer cloned.
+func (ct *ColumnTransformer) Clone() (core.Transformer, error) {
+	specs := make([]ColumnSpec, len(ct.Transformers))
+
+	for k, spec := range ct.Transformers {
+		specs[k] = ColumnSpec{
+			Name:        spec.Name,
+			Columns:     append([]int(nil), spec.Columns...),
+			ColumnNames: append([]string(nil), spec.ColumnNames...),
+		}
+		if spec.Transformer != nil {
+			t, err := core.CloneTransformer(spec.Transformer)
+			if err != nil {
+				return nil, fmt.Errorf("step %q: %w", spec.Name, err)
+			}
+			specs[k].Transformer = t
+		}
+	}
+
+	out := NewColumnTransformer(specs, ct.Remainder)
+	out.FeatureNames = append([]string(nil), ct.FeatureNames...)
+
+	return out, nil
+}
+
+func (ct *ColumnTransformer) IsFitted() bool {
+	return ct.Selected != nil
+}
+
+// resolve turns every spec's indices and names into column indices.
+func (ct *ColumnTransformer) resolve(nCols int) ([][]int, error) {
+	if len(ct.FeatureNames) > 0 && len(ct.FeatureNames) != nCols {
+		return nil, fmt.Errorf("%w: %d feature names for %d columns", validation.ErrShapeMismatch, len(ct.FeatureNames), nCols)
+	}
+
+	byName := make(map[string]int, len(ct.FeatureNames))
+	for j, name := range ct.FeatureNames {
+		byName[name] = j
+	}
+
+	selected := make([][]int, len(ct.Transformers))
+	for k, spec := range ct.Transformers {
+		cols := make([]int, 0, len(spec.Columns)+len(spec.ColumnNames))
+
+		for _, j := range spec.Columns {
+			if j < 0 || j >= nCols {
+				return nil, fmt.Errorf("step %q: column %d out of range for %d columns", spec.Name, j, nCols)
+			}
+			cols = append(cols, j)
+		}
+		for _, name := range spec.ColumnNames {
+			j, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("step %q: unknown column %q", spec.Name, name)
+			}
+			cols = append(cols, j)
+		}
+
+		if len(cols) == 0 {
+			return nil, fmt.Errorf("step %q selects no columns", spec.Name)
+		}
+		selected[k] = cols
+	}
+
+	return selected, nil
+}
+
+// stack concatenates the spec outputs and, with passthrough, the
+// remaining input columns.
+func (ct *ColumnTransformer) stack(X matrix.Matrix, parts []matrix.Matrix) (matrix.Matrix, error) {
+	if ct.Remainder == "passthrough" && len(ct.RemainderColumns) > 0 {
+		parts = append(parts, X.SelectCols(ct.RemainderColumns))
+	}
+	if len(parts) == 0 {
+		return matrix.Matrix{}, errors.New("column transformer produces no columns")
+	}
+
+	return matrix.HStack(parts...)
+}
+
+func (ct *ColumnTransformer) outputWidth() int {
+	n := 0
+	for _, w := range ct.OutputWidths {
+		n += w
+	}
+	if ct.Remainder == "passthrough" {
+		n += len(ct.RemainderColumns)
+	}
+
+	return n
+}
+
+func remainderColumns(nCols int, selected [][]int) []int {
+	used := make([]bool, nCols)
+	for _, cols := range selected {
+		for _, j := range cols {
+			used[j] = true
+		}
+	}
+
+	rest := []int{}
+	for j, u := range used {
+		if !u {
+			rest = append(rest, j)
+		}
+	}
+
+	return rest
+}
+
+// scatterCols copies column k of src into column cols[k] of dst.
+func scatterCols(dst, src matrix.Matrix, cols []int) {
+	for i := 0; i < dst.Rows; i++ {
+		d, s := dst.Row(i), src.Row(i)
+		for k, j := range cols {
+			d[j] = s[k]
+		}
+	}
+}
+
+func (ct *ColumnTransformer) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewEncoder(f).Encode(ct)
+}
+
+func (ct *ColumnTransformer) Load(path string) error {
+	return core.LoadGob(path, ct)
+}
+
+// GetParams returns "remainder" and every spec transformer's parameters
+// prefixed with the spec name and "__".
+func (ct *ColumnTransformer) GetParams() map[string]interface{} {
+	params := prefixedParams(ct.components())
+	params["remainder"] = ct.Remainder
+
+	return params
+}
+
+func (ct *ColumnTransformer) SetParams(params map[string]interface{}) error {
+	rest := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		if k != "remainder" {
+			rest[k] = v
+		}
+	}
+
+	if err := setPrefixedParams(ct.components(), rest); err != nil {
+		return err
+	}
+	if v, ok := params["remainder"].(string); ok {
+		ct.Remainder = v
+	}
+
+	return nil
+}
+
+func (ct *ColumnTransformer) components() map[string]interface{} {
+	out := make(map[string]interface{}, len(ct.Transformers))
+
+	for _, spec := range ct.Transformers {
+		if spec.Transformer != nil {
+			out[spec.Name] = spec.Transformer
+		}
+	}
+
+	return out
+}
+
+
+var _ core.Transformer = (*ColumnTransformer)(nil)
+var _ core.Serializable = (*ColumnTransformer)(nil)
+var _ core.Params = (*ColumnTransformer)(nil)
+var _ core.TransformerCloner = (*ColumnTransformer)(nil)
@@ -0,0 +1,97 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golearn-lite/core"
+)
+
+// paramSep separates a component name from its parameter name.
+const paramSep = "__"
+
+// prefixedParams merges the parameters of every component that has any,
+// keyed as "name__param".
+func prefixedParams(components map[string]interface{}) map[string]interface{} {
+	params := make(map[string]interface{})
+
+	for name, component := range components {
+		c, ok := component.(core.Params)
+		if !ok {
+			continue
+		}
+		for k, v := range c.GetParams() {
+			params[name+paramSep+k] = v
+		}
+	}
+
+	return params
+}
+
+// setPrefixedParams is the inverse of prefixedParams. Every key is checked
+// before any component is changed, and if a component's SetParams rejects
+// a value, every component touched so far, including that one, gets its
+// previous parameters back.
+func setPrefixedParams(components map[string]interface{}, params map[string]interface{}) error {
+	grouped := make(map[string]map[string]interface{})
+
+	for key, v := range params {
+		name, param, ok := strings.Cut(key, paramSep)
+		if !ok {
+			return fmt.Errorf("parameter %q is not of the form component__param", key)
+		}
+		component, ok := components[name]
+		if !ok {
+			return fmt.Errorf("parameter %q: no step named %q", key, name)
+		}
+		if _, ok := component.(core.Params); !ok {
+			return fmt.Errorf("parameter %q: step %q has no parameters", key, name)
+		}
+
+		if grouped[name] == nil {
+			grouped[name] = make(map[string]interface{})
+		}
+		grouped[name][param] = v
+	}
+
+	// Keep the current values so a rejection part way through can be undone
+	previous := make(map[string]map[string]interface{}, len(grouped))
+	for name := range grouped {
+		previous[name] = components[name].(core.Params).GetParams()
+	}
+
+	var applied []string
+	for name, sub := range grouped {
+		applied = append(applied, name)
+		if err := components[name].(core.Params).SetParams(sub); err != nil {
+			for _, done := range applied {
+				components[done].(core.Params).SetParams(previous[done])
+			}
+			return fmt.Errorf("step %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// checkNames requires component names to be non-empty, unique and free of
+// the parameter separator.
+func checkNames(names []string) error {
+	seen := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		if name == "" {
+			return errors.New("step names must not be empty")
+		}
+		if strings.Contains(name, paramSep) {
+			return fmt.Errorf("step name %q must not contain %q", name, paramSep)
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate step name %q", name)
+		}
+		seen[name] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package pipeline
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+
+	"golearn-lite/core"
+	"golearn-lite/matrix"
+	"golearn-lite/validation"
+)
+
+
+func init() {
+	gob.Register(&Pipeline{})
+}
+
+
+// Step is a named transformer in a Pipeline. The name prefixes the step's
+// parameters, as in "scaler__with_mean".
+type Step struct {
+	Name        string
+	Transformer core.Transformer
+}
+
+// Pipeline fits its steps in order, each on the output of the previous one,
+// then fits Estimator on the result. Predict replays the fitted steps, so
+// training and serving always apply the same preprocessing.
+//
+// Save and Load use gob, which needs the concrete types of the steps and
+// the estimator to be registered; every type in this module registers
+// itself, and custom types must call gob.Register.
+type Pipeline struct {
+	Steps         []Step
+	EstimatorName string
+	Estimator     core.Model
+}
+
+func NewPipeline(steps []Step, estimatorName string, estimator core.Model) *Pipeline {
+	return &Pipeline{
+		Steps:         steps,
+		EstimatorName: estimatorName,
+		Estimator:     estimator,
+	}
+}
+
+func (p *Pipeline) Fit(X matrix.Matrix, y []float64) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+
+	X, err := fitTransformSteps(p.Steps, X)
+	if err != nil {
+		return err
+	}
+
+	if err := p.Estimator.Fit(X, y); err != nil {
+		return fmt.Errorf("step %q: %w", p.EstimatorName, err)
+	}
+
+	return nil
+}
+
+func (p *Pipeline) Predict(X matrix.Matrix) []float64 {
+	preds, _ := p.PredictE(X)
+	return preds
+}
+
+func (p *Pipeline) PredictE(X matrix.Matrix) ([]float64, error) {
+	if err := validation.CheckFitted(p.IsFitted()); err != nil {
+		return nil, err
+	}
+
+	Xt, err := transformSteps(p.Steps, X)
+	if err != nil {
+		return nil, err
+	}
+
+	return core.Predict(p.Estimator, Xt)
+}
+
+// IsFitted reports whether the estimator and every step that can tell are
+// fitted.
+func (p *Pipeline) IsFitted() bool {
+	if p.Estimator == nil {
+		return false
+	}
+	if f, ok := p.Estimator.(fittable); ok && !f.IsFitted() {
+		return false
+	}
+
+	return stepsFitted(p.Steps)
+}
+
+// Clone returns an unfitted copy with every step and the estimator cloned.
+func (p *Pipeline) Clone() (core.Model, error) {
+	steps, err := cloneSteps(p.Steps)
+	if err != nil {
+		return nil, err
+	}
+
+	estimator, err := core.Clone(p.Estimator)
+	if err != nil {
+		return nil, fmt.Errorf("step %q: %w", p.EstimatorName, err)
+	}
+
+	return NewPipeline(steps, p.EstimatorName, estimator), nil
+}
+
+func (p *Pipeline) Score(X matrix.Matrix, y []float64, metric func(yTrue, yPred []float64) float64) float64 {
+	return core.Score(p.PredictE, X, y, metric)
+}
+
+func (p *Pipeline) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewEncoder(f).Encode(p)
+}
+
+func (p *Pipeline) Load(path string) error {
+	return core.LoadGob(path, p)
+}
+
+// GetParams collects the parameters of every step and of the estimator,
+// each prefixed with its component name and "__".
+func (p *Pipeline) GetParams() map[string]interface{} {
+	return prefixedParams(p.components())
+}
+
+// SetParams routes each "name__param" key to the named component. Keys
+// without a known component prefix are rejected before anything is set.
+func (p *Pipeline) SetParams(params map[string]interface{}) error {
+	return setPrefixedParams(p.components(), params)
+}
+
+// components maps every step name and the estimator name to its value.
+func (p *Pipeline) components() map[string]interface{} {
+	out := make(map[string]interface{}, len(p.Steps)+1)
+
+	for _, step := range p.Steps {
+		out[step.Name] = step.Transformer
+	}
+	if p.Estimator != nil {
+		out[p.EstimatorName] = p.Estimator
+	}
+
+	return out
+}
+
+// validate checks that an estimator is set and that every step is usable.
+func (p *Pipeline) validate() error {
+	if p.Estimator == nil {
+		return errors.New("pipeline has no estimator")
+	}
+
+	names, err := stepNames(p.Steps)
+	if err != nil {
+		return err
+	}
+
+	return checkNames(append(names, p.EstimatorName))
+}
+
+
+var _ core.Model = (*Pipeline)(nil)
+var _ core.Estimator = (*Pipeline)(nil)
+var _ core.Scorable = (*Pipeline)(nil)
+var _ core.Serializable = (*Pipeline)(nil)
+var _ core.Params = (*Pipeline)(nil)
+var _ core.Cloner = (*Pipeline)(nil)
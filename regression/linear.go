@@ -2,13 +2,17 @@ package regression
 
 import (
 	"encoding/gob"
-	"errors"
 	"os"
 
 	"golearn-lite/core"
 	"golearn-lite/matrix"
+	"golearn-lite/validation"
 )
 
+func init() {
+	gob.Register(&LinearRegression{})
+}
+
 type LinearRegression struct {
 	Coefficients []float64
 }
@@ -18,58 +22,73 @@ func NewLinearRegression() *LinearRegression {
 }
 
 func (lr *LinearRegression) Fit(X matrix.Matrix, y []float64) error {
-	if X.Rows != len(y) {
-		return errors.New("number of samples in X and y do not match")
+	if err := validation.CheckXY(X, y); err != nil {
+		return err
 	}
 
 	// Add bias term
 	Xb := addBias(X)
 
 	// Convert y to matrix
-	yMat := make([][]float64, len(y))
-
-	for i := range y {
-		yMat[i] = []float64{y[i]}
+	Y, err := matrix.NewFromSlice(len(y), 1, y)
+	if err != nil {
+		return err
 	}
-	Y := matrix.New(yMat)
 
-	XT := Xb.Transpose()
-	XTX, _ := XT.Dot(Xb)
-	XTX_inv, err := XTX.Inverse()
+	// Least squares via QR, falling back to the minimum-norm solution
+	// when the design is rank deficient
+	theta, err := matrix.LstSq(Xb, Y)
 	if err != nil {
 		return err
 	}
 
-	XTy, _ := XT.Dot(Y)
-	theta, _ := XTX_inv.Dot(XTy)
-
 	// Extract coefficients
 	lr.Coefficients = make([]float64, theta.Rows)
-	for i := range theta.Data {
-		lr.Coefficients[i] = theta.Data[i][0]
+	for i := 0; i < theta.Rows; i++ {
+		lr.Coefficients[i] = theta.At(i, 0)
 	}
 
 	return nil
 }
 
 func (lr *LinearRegression) Predict(X matrix.Matrix) []float64 {
+	preds, _ := lr.PredictE(X)
+	return preds
+}
+
+func (lr *LinearRegression) PredictE(X matrix.Matrix) ([]float64, error) {
+	if err := validation.CheckFitted(lr.IsFitted()); err != nil {
+		return nil, err
+	}
+	if err := validation.CheckPredict(X, len(lr.Coefficients)-1); err != nil {
+		return nil, err
+	}
+
 	Xb := addBias(X)
 	pred := make([]float64, Xb.Rows)
 
 	for i := 0; i < Xb.Rows; i++ {
+		row := Xb.Row(i)
 		sum := 0.0
 		for j := 0; j < Xb.Cols; j++ {
-			sum += Xb.Data[i][j] * lr.Coefficients[j]
+			sum += row[j] * lr.Coefficients[j]
 		}
 		pred[i] = sum
 	}
 
-	return pred
+	return pred, nil
+}
+
+func (lr *LinearRegression) IsFitted() bool {
+	return lr.Coefficients != nil
+}
+
+func (lr *LinearRegression) IsRegressor() bool {
+	return true
 }
 
 func (lr *LinearRegression) Score(X matrix.Matrix, y []float64, metric func(yTrue, yPred []float64) float64) float64 {
-	yPred := lr.Predict(X)
-	return metric(y, yPred)
+	return core.Score(lr.PredictE, X, y, metric)
 }
 
 func (lr *LinearRegression) Save(path string) error {
@@ -83,13 +102,7 @@ func (lr *LinearRegression) Save(path string) error {
 }
 
 func (lr *LinearRegression) Load(path string) error {
-	f, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	return gob.NewDecoder(f).Decode(&lr.Coefficients)
+	return core.LoadGob(path, &lr.Coefficients)
 }
 
 func (lr *LinearRegression) GetParams() map[string]interface{} {
@@ -101,20 +114,21 @@ func (lr *LinearRegression) SetParams(params map[string]interface{}) error {
 }
 
 func addBias(X matrix.Matrix) matrix.Matrix {
-	biased := make([][]float64, X.Rows)
+	biased := matrix.Zeros(X.Rows, X.Cols+1)
 
-	for i := range X.Data {
-		row := make([]float64, X.Cols+1)
+	for i := 0; i < X.Rows; i++ {
+		row := biased.Row(i)
 		row[0] = 1.0
-		copy(row[1:], X.Data[i])
-		biased[i] = row
+		copy(row[1:], X.Row(i))
 	}
 
-	return matrix.New(biased)
+	return biased
 }
 
 
 var _ core.Model = (*LinearRegression)(nil)
+var _ core.Estimator = (*LinearRegression)(nil)
 var _ core.Scorable = (*LinearRegression)(nil)
 var _ core.Serializable = (*LinearRegression)(nil)
 var _ core.Params = (*LinearRegression)(nil)
+var _ core.Regressor = (*LinearRegression)(nil)
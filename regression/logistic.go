@@ -8,13 +8,20 @@ import (
 
 	"golearn-lite/core"
 	"golearn-lite/matrix"
+	"golearn-lite/validation"
 )
 
 
+func init() {
+	gob.Register(&LogisticRegression{})
+}
+
+
 type LogisticRegression struct {
 	Coefficients []float64
 	LearningRate float64
 	Iterations int
+	Threshold float64	// Predict returns 1 when P(y = 1) >= Threshold; 0 means 0.5
 }
 
 
@@ -22,16 +29,30 @@ func NewLogisticRegression() *LogisticRegression {
 	return &LogisticRegression{
 		LearningRate: 0.1,
 		Iterations: 1000,
+		Threshold: 0.5,
 	}
 }
 
 func(lr *LogisticRegression) Fit(X matrix.Matrix, y[]float64) error {
-	if X.Rows != len(y) {
-		return errors.New("number of samples in X and y do not match")
+	return lr.FitMat(X, y)
+}
+
+// FitMat fits on any dense or sparse matrix. Coefficients[0] is the bias and
+// Coefficients[j+1] weights feature j.
+func (lr *LogisticRegression) FitMat(X matrix.Mat, y []float64) error {
+	if err := validation.CheckXY(X, y); err != nil {
+		return err
+	}
+	for _, label := range y {
+		if label != 0 && label != 1 {
+			return errors.New("logistic regression labels must be 0 or 1")
+		}
 	}
 
-	Xb := addBias(X)
-	nSamples, nFeatures := Xb.Rows, Xb.Cols
+	nSamples, nCols := X.Dims()
+
+	X = matrix.RowMajor(X)
+	nFeatures := nCols + 1
 
 	lr.Coefficients = make([]float64, nFeatures)
 
@@ -40,16 +61,21 @@ func(lr *LogisticRegression) Fit(X matrix.Matrix, y[]float64) error {
 
 		// Batch gradient descent
 		for i := 0; i < nSamples; i++ {
-			z := 0.0
-			for j := 0; j < nFeatures; j++ {
-				z += Xb.Data[i][j] * lr.Coefficients[j]
-			}
-
-			pred := sigmoid(z)
+			pred := sigmoid(lr.margin(X, i))
 			err := pred - y[i]
-			for j := 0; j < nFeatures; j++ {
-				gradients[j] += err * Xb.Data[i][j]
+
+			gradients[0] += err
+			switch dense := X.(type) {
+			case matrix.Matrix:
+				accumulate(gradients[1:], dense.Row(i), err)
+				continue
+			case matrix.Matrix32:
+				accumulate(gradients[1:], dense.Row(i), err)
+				continue
 			}
+			X.DoRowNonZero(i, func(j int, v float64) {
+				gradients[j+1] += err * v
+			})
 		}
 
 		// Update coefficients
@@ -62,26 +88,137 @@ func(lr *LogisticRegression) Fit(X matrix.Matrix, y[]float64) error {
 }
 
 func (lr *LogisticRegression) Predict(X matrix.Matrix) []float64 {
-	Xb := addBias(X)
-	nSamples := Xb.Rows
-	predictions := make([]float64, nSamples)
+	return lr.PredictMat(X)
+}
+
+func (lr *LogisticRegression) PredictMat(X matrix.Mat) []float64 {
+	preds, _ := lr.predict(X)
+	return preds
+}
+
+func (lr *LogisticRegression) PredictE(X matrix.Matrix) ([]float64, error) {
+	return lr.predict(X)
+}
+
+func (lr *LogisticRegression) IsFitted() bool {
+	return lr.Coefficients != nil
+}
+
+func (lr *LogisticRegression) PredictProba(X matrix.Matrix) (matrix.Matrix, error) {
+	return lr.PredictProbaMat(X)
+}
+
+// PredictProbaMat returns one column for class 0 and one for class 1.
+func (lr *LogisticRegression) PredictProbaMat(X matrix.Mat) (matrix.Matrix, error) {
+	probs, err := lr.positiveProba(X)
+	if err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	out := matrix.Zeros(len(probs), 2)
+	for i, p := range probs {
+		out.Set(i, 0, 1-p)
+		out.Set(i, 1, p)
+	}
+
+	return out, nil
+}
+
+func (lr *LogisticRegression) Classes() []float64 {
+	return []float64{0, 1}
+}
+
+func (lr *LogisticRegression) IsClassifier() bool {
+	return true
+}
+
+// DecisionFunction returns the raw margin w·x + b of every row; positive
+// margins favor class 1.
+func (lr *LogisticRegression) DecisionFunction(X matrix.Matrix) ([]float64, error) {
+	return lr.DecisionFunctionMat(X)
+}
+
+func (lr *LogisticRegression) DecisionFunctionMat(X matrix.Mat) ([]float64, error) {
+	if err := validation.CheckFitted(lr.IsFitted()); err != nil {
+		return nil, err
+	}
+	if err := validation.CheckPredict(X, len(lr.Coefficients)-1); err != nil {
+		return nil, err
+	}
+
+	nSamples, _ := X.Dims()
+	X = matrix.RowMajor(X)
+	margins := make([]float64, nSamples)
 
 	for i := 0; i < nSamples; i++ {
-		z := 0.0
-		for j := 0; j < Xb.Cols; j++ {
-			z += Xb.Data[i][j] * lr.Coefficients[j]
+		margins[i] = lr.margin(X, i)
+	}
+
+	return margins, nil
+}
+
+// predict thresholds P(y = 1) into 0/1 labels.
+func (lr *LogisticRegression) predict(X matrix.Mat) ([]float64, error) {
+	probs, err := lr.positiveProba(X)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := lr.threshold()
+	for i, p := range probs {
+		if p >= threshold {
+			probs[i] = 1
+		} else {
+			probs[i] = 0
 		}
+	}
+
+	return probs, nil
+}
+
+// threshold is the effective decision threshold. Models saved before
+// Threshold existed, and zero-value structs, have 0, which would label
+// every sample 1, so 0 means the default 0.5.
+func (lr *LogisticRegression) threshold() float64 {
+	if lr.Threshold == 0 {
+		return 0.5
+	}
+
+	return lr.Threshold
+}
+
+// positiveProba returns P(y = 1 | x) for every row of X.
+func (lr *LogisticRegression) positiveProba(X matrix.Mat) ([]float64, error) {
+	probs, err := lr.DecisionFunctionMat(X)
+	if err != nil {
+		return nil, err
+	}
 
-		p := sigmoid(z)
-		predictions[i] = p
+	for i, z := range probs {
+		probs[i] = sigmoid(z)
 	}
 
-	return predictions
+	return probs, nil
+}
+
+// margin is the linear score w·x + b for row i of X.
+func (lr *LogisticRegression) margin(X matrix.Mat, i int) float64 {
+	z := lr.Coefficients[0]
+	switch dense := X.(type) {
+	case matrix.Matrix:
+		return z + dot(dense.Row(i), lr.Coefficients[1:])
+	case matrix.Matrix32:
+		return z + dot(dense.Row(i), lr.Coefficients[1:])
+	}
+	X.DoRowNonZero(i, func(j int, v float64) {
+		z += v * lr.Coefficients[j+1]
+	})
+
+	return z
 }
 
 func (lr *LogisticRegression) Score(X matrix.Matrix, y []float64, metric func(yTrue, yPred []float64) float64) float64 {
-	yPred := lr.Predict(X)
-	return metric(y, yPred)
+	return core.Score(lr.PredictE, X, y, metric)
 }
 
 func (lr *LogisticRegression) Save(path string) error {
@@ -95,19 +232,14 @@ func (lr *LogisticRegression) Save(path string) error {
 }
 
 func (lr *LogisticRegression) Load(path string) error {
-	f, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	return gob.NewDecoder(f).Decode(lr)
+	return core.LoadGob(path, lr)
 }
 
 func (lr *LogisticRegression) GetParams() map[string]interface{} {
 	return map[string]interface{} {
 		"learning_rate": lr.LearningRate,
 		"iterations": lr.Iterations,
+		"threshold": lr.threshold(),
 	}
 }
 
@@ -118,16 +250,41 @@ func (lr *LogisticRegression) SetParams(params map[string]interface{}) error {
 	if v, ok := params["iterations"].(int); ok {
 		lr.Iterations = v
 	}
+	if v, ok := params["threshold"].(float64); ok {
+		if v <= 0 || v >= 1 {
+			return errors.New("threshold must be strictly between 0 and 1")
+		}
+		lr.Threshold = v
+	}
 
 	return nil
 }
 
+// dot and accumulate serve the dense float64 and float32 fast paths.
+func dot[T matrix.Float](row []T, w []float64) float64 {
+	sum := 0.0
+	for j, v := range row {
+		sum += float64(v) * w[j]
+	}
+
+	return sum
+}
+
+func accumulate[T matrix.Float](dst []float64, row []T, scale float64) {
+	for j, v := range row {
+		dst[j] += scale * float64(v)
+	}
+}
+
 func sigmoid(z float64) float64 {
 	return 1.0 / (1.0 + math.Exp(-z))
 }
 
 
 var _ core.Model = (*LogisticRegression)(nil)
+var _ core.Estimator = (*LogisticRegression)(nil)
 var _ core.Scorable = (*LogisticRegression)(nil)
 var _ core.Serializable = (*LogisticRegression)(nil)
-var _ core.Params = (*LogisticRegression)(nil)
\ No newline at end of file
+var _ core.Params = (*LogisticRegression)(nil)
+var _ core.ProbabilisticClassifier = (*LogisticRegression)(nil)
+var _ core.Classifier = (*LogisticRegression)(nil)
@@ -0,0 +1,54 @@
+package regression
+
+import (
+	"testing"
+
+	"golearn-lite/core"
+	"golearn-lite/matrix"
+)
+
+func separable() (matrix.Matrix, []float64) {
+	return matrix.New([][]float64{{-2}, {-1}, {1}, {2}}), []float64{0, 0, 1, 1}
+}
+
+func TestZeroThresholdMeansDefault(t *testing.T) {
+	X, y := separable()
+	lr := &LogisticRegression{LearningRate: 0.1, Iterations: 500}
+
+	if err := lr.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	preds, err := lr.PredictE(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range y {
+		if preds[i] != y[i] {
+			t.Fatalf("predictions %v, want %v", preds, y)
+		}
+	}
+
+	if got := lr.GetParams()["threshold"]; got != 0.5 {
+		t.Errorf("threshold param = %v, want 0.5", got)
+	}
+}
+
+func TestCloneZeroValueLogistic(t *testing.T) {
+	m, err := core.Clone(&LogisticRegression{LearningRate: 0.1, Iterations: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clone := m.(*LogisticRegression)
+	if clone.LearningRate != 0.1 || clone.Iterations != 10 || clone.Threshold != 0.5 {
+		t.Errorf("clone = %+v", clone)
+	}
+}
+
+func TestSetParamsRejectsThreshold(t *testing.T) {
+	for _, v := range []float64{0, -0.1, 1, 1.5} {
+		if err := NewLogisticRegression().SetParams(map[string]interface{}{"threshold": v}); err == nil {
+			t.Errorf("threshold %v accepted", v)
+		}
+	}
+}
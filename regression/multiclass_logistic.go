@@ -6,11 +6,17 @@ import (
 
 	"golearn-lite/core"
 	"golearn-lite/matrix"
+	"golearn-lite/validation"
 )
 
 
+func init() {
+	gob.Register(&MultiClassLogisticRegression{})
+}
+
+
 type MultiClassLogisticRegression struct {
-	Classes				[]float64
+	ClassLabels			[]float64
 	Classifiers			map[float64]*LogisticRegression
 	LearningRate 		float64
 	Iterations			int
@@ -26,12 +32,14 @@ func NewMultiClassLogisticRegression() *MultiClassLogisticRegression {
 }
 
 func (mc *MultiClassLogisticRegression) Fit(X matrix.Matrix, y []float64) error {
-	classSet := make(map[float64]bool)
-	for _, label := range y {
-		classSet[label] = true
+	if err := validation.CheckXY(X, y); err != nil {
+		return err
 	}
 
-	for class := range classSet {
+	mc.ClassLabels = core.UniqueClasses(y)
+	mc.Classifiers = make(map[float64]*LogisticRegression)
+
+	for _, class := range mc.ClassLabels {
 		binaryY := make([]float64, len(y))
 		for i, label := range y {
 			if label == class {
@@ -50,41 +58,112 @@ func (mc *MultiClassLogisticRegression) Fit(X matrix.Matrix, y []float64) error
 		}
 
 		mc.Classifiers[class] = clf
-		mc.Classes = append(mc.Classes, class)
 	}
 
 	return nil
 }
 
 func (mc * MultiClassLogisticRegression) Predict(X matrix.Matrix) []float64 {
-	n := X.Rows
-	scores := make([][]float64, len(mc.Classes))
+	preds, _ := mc.PredictE(X)
+	return preds
+}
+
+func (mc *MultiClassLogisticRegression) PredictE(X matrix.Matrix) ([]float64, error) {
+	scores, err := mc.ovrScores(X)
+	if err != nil {
+		return nil, err
+	}
 
-	for i, class := range mc.Classes {
-		probs := mc.Classifiers[class].Predict(X)
-		scores[i] = probs
+	best, _ := scores.ArgMaxAxis(matrix.AxisCols)
+	preds := make([]float64, len(best))
+	for i, k := range best {
+		preds[i] = mc.ClassLabels[k]
 	}
 
-	preds := make([]float64, n)
-	for i := 0; i < n; i++ {
-		maxProb := scores[0][i]
-		bestClass := mc.Classes[0]
-		for j := 1; j < len(mc.Classes); j++ {
-			if scores[j][i] > maxProb {
-				maxProb = scores[j][i]
-				bestClass = mc.Classes[j]
+	return preds, nil
+}
+
+// PredictProba normalizes the one-vs-rest probabilities of each sample so
+// they sum to one.
+func (mc *MultiClassLogisticRegression) PredictProba(X matrix.Matrix) (matrix.Matrix, error) {
+	scores, err := mc.ovrScores(X)
+	if err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	sums, _ := scores.SumAxis(matrix.AxisCols)
+	for i, sum := range sums {
+		row := scores.Row(i)
+		for k := range row {
+			if sum > 0 {
+				row[k] /= sum
+			} else {
+				row[k] = 1 / float64(len(row))
 			}
 		}
+	}
+
+	return scores, nil
+}
+
+func (mc *MultiClassLogisticRegression) Classes() []float64 {
+	return append([]float64(nil), mc.ClassLabels...)
+}
+
+func (mc *MultiClassLogisticRegression) IsClassifier() bool {
+	return true
+}
 
-		preds[i] = bestClass
+// DecisionFunction returns each one-vs-rest classifier's raw margin, one
+// column per entry of ClassLabels.
+func (mc *MultiClassLogisticRegression) DecisionFunction(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := validation.CheckFitted(mc.IsFitted()); err != nil {
+		return matrix.Matrix{}, err
 	}
 
-	return preds
+	margins := matrix.Zeros(X.Rows, len(mc.ClassLabels))
+
+	for k, class := range mc.ClassLabels {
+		m, err := mc.Classifiers[class].DecisionFunction(X)
+		if err != nil {
+			return matrix.Matrix{}, err
+		}
+		for i, v := range m {
+			margins.Set(i, k, v)
+		}
+	}
+
+	return margins, nil
+}
+
+// ovrScores holds each binary classifier's positive-class probability, one
+// column per entry of ClassLabels.
+func (mc *MultiClassLogisticRegression) ovrScores(X matrix.Matrix) (matrix.Matrix, error) {
+	if err := validation.CheckFitted(mc.IsFitted()); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	scores := matrix.Zeros(X.Rows, len(mc.ClassLabels))
+
+	for k, class := range mc.ClassLabels {
+		probs, err := mc.Classifiers[class].positiveProba(X)
+		if err != nil {
+			return matrix.Matrix{}, err
+		}
+		for i, p := range probs {
+			scores.Set(i, k, p)
+		}
+	}
+
+	return scores, nil
+}
+
+func (mc *MultiClassLogisticRegression) IsFitted() bool {
+	return len(mc.ClassLabels) > 0
 }
 
 func (mc *MultiClassLogisticRegression) Score(X matrix.Matrix, y []float64, metric func(yTrue, yPred []float64) float64) float64 {
-	yPred := mc.Predict(X)
-	return metric(y, yPred)
+	return core.Score(mc.PredictE, X, y, metric)
 }
 
 func (mc *MultiClassLogisticRegression) Save(path string) error {
@@ -98,13 +177,7 @@ func (mc *MultiClassLogisticRegression) Save(path string) error {
 }
 
 func (mc *MultiClassLogisticRegression) Load(path string) error {
-	f, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	return gob.NewDecoder(f).Decode(mc)
+	return core.LoadGob(path, mc)
 }
 
 func (mc *MultiClassLogisticRegression) GetParams() map[string]interface{} {
@@ -127,6 +200,9 @@ func (mc *MultiClassLogisticRegression) SetParams(params map[string]interface{})
 
 
 var _ core.Model = (*MultiClassLogisticRegression)(nil)
+var _ core.Estimator = (*MultiClassLogisticRegression)(nil)
 var _ core.Scorable = (*MultiClassLogisticRegression)(nil)
 var _ core.Serializable = (*MultiClassLogisticRegression)(nil)
-var _ core.Params = (*MultiClassLogisticRegression)(nil)
\ No newline at end of file
+var _ core.Params = (*MultiClassLogisticRegression)(nil)
+var _ core.ProbabilisticClassifier = (*MultiClassLogisticRegression)(nil)
+var _ core.Classifier = (*MultiClassLogisticRegression)(nil)
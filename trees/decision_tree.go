@@ -8,19 +8,23 @@ import (
 
 	"golearn-lite/core"
 	"golearn-lite/matrix"
+	"golearn-lite/validation"
 )
 
 
 func init() {
+	gob.Register(&DecisionTree{})
 	gob.Register(&TreeNode{})
 }
 
 
 type DecisionTree struct {
-	Root     *TreeNode
-	MaxDepth int
-	MinSize  int
-	Task     string // "classification" or "regression"
+	Root        *TreeNode
+	NFeatures   int
+	ClassLabels []float64 // distinct training labels, classification only
+	MaxDepth    int
+	MinSize     int
+	Task        string // "classification" or "regression"
 }
 
 type TreeNode struct {
@@ -42,19 +46,32 @@ func NewDecisionTree(task string) *DecisionTree {
 }
 
 func (dt *DecisionTree) Fit(X matrix.Matrix, y []float64) error {
-	if X.Rows != len(y) {
-		return errors.New("number of rows in X must equal length of y")
+	if err := validation.CheckXY(X, y); err != nil {
+		return err
+	}
+
+	idx := make([]int, X.Rows)
+	for i := range idx {
+		idx[i] = i
 	}
 
-	dt.Root = dt.buildTree(X.Data, y, 0)
+	dt.NFeatures = X.Cols
+	dt.ClassLabels = nil
+	if dt.Task == "classification" {
+		dt.ClassLabels = core.UniqueClasses(y)
+	}
+	dt.Root = dt.buildTree(X, y, idx, 0)
 	return nil
 }
 
-func (dt *DecisionTree) buildTree(X [][]float64, y []float64, depth int) *TreeNode {
-	if len(y) == 0 {
+// buildTree grows a subtree over the rows of X listed in idx.
+func (dt *DecisionTree) buildTree(X matrix.Matrix, yAll []float64, idx []int, depth int) *TreeNode {
+	if len(idx) == 0 {
 		return nil
 	}
 
+	y := gather(yAll, idx)
+
 	// leaf condition
 	if depth >= dt.MaxDepth || len(y) <= dt.MinSize || dt.isPure(y) {
 		if dt.Task == "classification" {
@@ -65,7 +82,7 @@ func (dt *DecisionTree) buildTree(X [][]float64, y []float64, depth int) *TreeNo
 	}
 
 	// Best split
-	bestIdx, bestThresh, _, leftX, rightX, leftY, rightY := dt.bestSplit(X, y)
+	bestIdx, bestThresh, _, leftIdx, rightIdx := dt.bestSplit(X, yAll, idx)
 	if bestIdx == -1 {
 		if dt.Task == "classification" {
 			return dt.makeClassificationLeaf(y)
@@ -77,24 +94,82 @@ func (dt *DecisionTree) buildTree(X [][]float64, y []float64, depth int) *TreeNo
 	return &TreeNode{
 		FeatureIndex: bestIdx,
 		Threshold:    bestThresh,
-		Left:         dt.buildTree(leftX, leftY, depth+1),
-		Right:        dt.buildTree(rightX, rightY, depth+1),
+		Left:         dt.buildTree(X, yAll, leftIdx, depth+1),
+		Right:        dt.buildTree(X, yAll, rightIdx, depth+1),
 	}
 }
 
 func (dt *DecisionTree) Predict(X matrix.Matrix) []float64 {
+	preds, _ := dt.PredictE(X)
+	return preds
+}
+
+func (dt *DecisionTree) PredictE(X matrix.Matrix) ([]float64, error) {
+	if err := validation.CheckFitted(dt.IsFitted()); err != nil {
+		return nil, err
+	}
+	if err := validation.CheckPredict(X, dt.NFeatures); err != nil {
+		return nil, err
+	}
+
 	preds := make([]float64, X.Rows)
 
-	for i, row := range X.Data {
-		preds[i] = dt.predictOne(dt.Root, row)
+	for i := 0; i < X.Rows; i++ {
+		preds[i] = dt.predictOne(dt.Root, X.Row(i))
 	}
 
-	return preds
+	return preds, nil
+}
+
+// PredictProba reports the class frequencies of the training samples in
+// the leaf each row falls into.
+func (dt *DecisionTree) PredictProba(X matrix.Matrix) (matrix.Matrix, error) {
+	if dt.Task != "classification" {
+		return matrix.Matrix{}, errors.New("PredictProba requires a classification task")
+	}
+	if err := validation.CheckFitted(dt.IsFitted()); err != nil {
+		return matrix.Matrix{}, err
+	}
+	if err := validation.CheckPredict(X, dt.NFeatures); err != nil {
+		return matrix.Matrix{}, err
+	}
+
+	proba := matrix.Zeros(X.Rows, len(dt.ClassLabels))
+
+	for i := 0; i < X.Rows; i++ {
+		leaf := findLeaf(dt.Root, X.Row(i))
+		total := 0
+		for _, count := range leaf.ClassCounts {
+			total += count
+		}
+
+		row := proba.Row(i)
+		for k, class := range dt.ClassLabels {
+			row[k] = float64(leaf.ClassCounts[class]) / float64(total)
+		}
+	}
+
+	return proba, nil
+}
+
+func (dt *DecisionTree) Classes() []float64 {
+	return append([]float64(nil), dt.ClassLabels...)
+}
+
+func (dt *DecisionTree) IsClassifier() bool {
+	return dt.Task == "classification"
+}
+
+func (dt *DecisionTree) IsRegressor() bool {
+	return dt.Task != "classification"
+}
+
+func (dt *DecisionTree) IsFitted() bool {
+	return dt.Root != nil
 }
 
 func (dt *DecisionTree) Score(X matrix.Matrix, y []float64, metric func(yTrue, yPred []float64) float64) float64 {
-	yPred := dt.Predict(X)
-	return metric(y, yPred)
+	return core.Score(dt.PredictE, X, y, metric)
 }
 
 func (dt *DecisionTree) Save(path string) error {
@@ -108,13 +183,7 @@ func (dt *DecisionTree) Save(path string) error {
 }
 
 func (dt *DecisionTree) Load(path string) error {
-	f, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	return gob.NewDecoder(f).Decode(dt)
+	return core.LoadGob(path, dt)
 }
 
 func (dt *DecisionTree) GetParams() map[string]interface{} {
@@ -139,26 +208,27 @@ func (dt *DecisionTree) SetParams(params map[string]interface{}) error {
 	return nil
 }
 
-func (dt *DecisionTree) bestSplit(X [][]float64, y []float64) (
+func (dt *DecisionTree) bestSplit(X matrix.Matrix, y []float64, idx []int) (
 	bestIdx int,
 	bestThresh float64,
 	bestScore float64,
-	leftX, rightX [][]float64,
-	leftY, rightY []float64,
+	leftIdx, rightIdx []int,
 ) {
-	nFeatures := len(X[0])
+	nFeatures := X.Cols
 	bestScore = math.Inf(1)
 	bestIdx = -1
 
 	for featureIdx := 0; featureIdx < nFeatures; featureIdx++ {
-		thresholds := uniqueValues(X, featureIdx)
+		thresholds := uniqueValues(X, idx, featureIdx)
 
 		for _, threshold := range thresholds {
-			currLeftX, currRightX, currLeftY, currRightY := splitDataset(X, y, featureIdx, threshold)
-			if len(currLeftY) == 0 || len(currRightY) == 0 {
+			currLeft, currRight := splitIndices(X, idx, featureIdx, threshold)
+			if len(currLeft) == 0 || len(currRight) == 0 {
 				continue
 			}
 
+			currLeftY, currRightY := gather(y, currLeft), gather(y, currRight)
+
 			var score float64
 
 			if dt.Task == "classification" {
@@ -171,8 +241,7 @@ func (dt *DecisionTree) bestSplit(X [][]float64, y []float64) (
 				bestScore = score
 				bestIdx = featureIdx
 				bestThresh = threshold
-				leftX, rightX = currLeftX, currRightX
-				leftY, rightY = currLeftY, currRightY
+				leftIdx, rightIdx = currLeft, currRight
 			}
 		}
 	}
@@ -181,19 +250,24 @@ func (dt *DecisionTree) bestSplit(X [][]float64, y []float64) (
 }
 
 func (dt *DecisionTree) predictOne(node *TreeNode, x []float64) float64 {
-	if node.IsLeaf {
-		if dt.Task == "classification" {
-			return majorityLabel(node.ClassCounts)
-		}
-
-		return node.Value
+	leaf := findLeaf(node, x)
+	if dt.Task == "classification" {
+		return majorityLabel(leaf.ClassCounts)
 	}
 
-	if x[node.FeatureIndex] < node.Threshold {
-		return dt.predictOne(node.Left, x)
+	return leaf.Value
+}
+
+func findLeaf(node *TreeNode, x []float64) *TreeNode {
+	for !node.IsLeaf {
+		if x[node.FeatureIndex] < node.Threshold {
+			node = node.Left
+		} else {
+			node = node.Right
+		}
 	}
 
-	return dt.predictOne(node.Right, x)
+	return node
 }
 
 func (dt *DecisionTree) makeRegressionLeaf(y []float64) *TreeNode {
@@ -248,28 +322,33 @@ func (dt *DecisionTree) isPure(y []float64) bool {
 	return true
 }
 
-func splitDataset(X [][]float64, y []float64, featureIdx int, threshold float64) (
-	leftX, rightX [][]float64,
-	leftY, rightY []float64,
-) {
-	for i, row := range X {
-		if row[featureIdx] < threshold {
-			leftX = append(leftX, row)
-			leftY = append(leftY, y[i])
+func splitIndices(X matrix.Matrix, idx []int, featureIdx int, threshold float64) (left, right []int) {
+	for _, i := range idx {
+		if X.At(i, featureIdx) < threshold {
+			left = append(left, i)
 		} else {
-			rightX = append(rightX, row)
-			rightY = append(rightY, y[i])
+			right = append(right, i)
 		}
 	}
 
 	return
 }
 
-func uniqueValues(X [][]float64, featureIdx int) []float64 {
+func gather(y []float64, idx []int) []float64 {
+	out := make([]float64, len(idx))
+
+	for k, i := range idx {
+		out[k] = y[i]
+	}
+
+	return out
+}
+
+func uniqueValues(X matrix.Matrix, idx []int, featureIdx int) []float64 {
 	seen := make(map[float64]bool)
 
-	for _, row := range X {
-		seen[row[featureIdx]] = true
+	for _, i := range idx {
+		seen[X.At(i, featureIdx)] = true
 	}
 
 	out := make([]float64, 0, len(seen))
@@ -329,6 +408,10 @@ func mse(yLeft, yRight []float64) float64 {
 
 
 var _ core.Model = (*DecisionTree)(nil)
+var _ core.Estimator = (*DecisionTree)(nil)
 var _ core.Scorable = (*DecisionTree)(nil)
 var _ core.Serializable = (*DecisionTree)(nil)
 var _ core.Params = (*DecisionTree)(nil)
+var _ core.ProbabilisticClassifier = (*DecisionTree)(nil)
+var _ core.Classifier = (*DecisionTree)(nil)
+var _ core.Regressor = (*DecisionTree)(nil)
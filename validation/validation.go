@@ -0,0 +1,109 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"golearn-lite/matrix"
+)
+
+// Sentinel errors returned (possibly wrapped) by the checks below and by
+// every model's Fit and Predict. Test for them with errors.Is.
+var (
+	ErrShapeMismatch = errors.New("shape mismatch")
+	ErrNotFitted     = errors.New("model is not fitted")
+	ErrNonFinite     = errors.New("input contains NaN or Inf")
+	ErrTooFewSamples = errors.New("too few samples")
+)
+
+// CheckXY validates training input: at least one sample, matching sample
+// counts, and finite values throughout.
+func CheckXY(X matrix.Mat, y []float64) error {
+	rows, _ := X.Dims()
+	if rows != len(y) {
+		return fmt.Errorf("%w: X has %d samples but y has %d", ErrShapeMismatch, rows, len(y))
+	}
+	if err := CheckX(X); err != nil {
+		return err
+	}
+
+	return CheckFiniteVector(y)
+}
+
+// CheckX validates that X has at least one sample and only finite values.
+func CheckX(X matrix.Mat) error {
+	rows, cols := X.Dims()
+	if err := CheckMinSamples(rows, 1); err != nil {
+		return err
+	}
+	if cols == 0 {
+		return fmt.Errorf("%w: X has no features", ErrShapeMismatch)
+	}
+
+	return CheckFinite(X)
+}
+
+// CheckPredict validates prediction input against the feature count seen
+// during Fit.
+func CheckPredict(X matrix.Mat, nFeatures int) error {
+	if err := CheckFeatures(X, nFeatures); err != nil {
+		return err
+	}
+
+	return CheckFinite(X)
+}
+
+func CheckFinite(X matrix.Mat) error {
+	rows, _ := X.Dims()
+	X = matrix.RowMajor(X)
+
+	for i := 0; i < rows; i++ {
+		bad := -1
+		X.DoRowNonZero(i, func(j int, v float64) {
+			if bad < 0 && (math.IsNaN(v) || math.IsInf(v, 0)) {
+				bad = j
+			}
+		})
+		if bad >= 0 {
+			return fmt.Errorf("%w: X[%d][%d]", ErrNonFinite, i, bad)
+		}
+	}
+
+	return nil
+}
+
+func CheckFiniteVector(y []float64) error {
+	for i, v := range y {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%w: y[%d]", ErrNonFinite, i)
+		}
+	}
+
+	return nil
+}
+
+func CheckFeatures(X matrix.Mat, nFeatures int) error {
+	_, cols := X.Dims()
+	if cols != nFeatures {
+		return fmt.Errorf("%w: X has %d features but the model was fitted with %d", ErrShapeMismatch, cols, nFeatures)
+	}
+
+	return nil
+}
+
+func CheckMinSamples(n, min int) error {
+	if n < min {
+		return fmt.Errorf("%w: got %d, need at least %d", ErrTooFewSamples, n, min)
+	}
+
+	return nil
+}
+
+func CheckFitted(fitted bool) error {
+	if !fitted {
+		return ErrNotFitted
+	}
+
+	return nil
+}